@@ -18,12 +18,16 @@ type baseCell struct {
 	refractoryPeriod float64
 	refractoryCnt    float64
 	refractoryState  bool
+
+	// The simulation this cell belongs to.
+	ctx *deuron.Context
 }
 
-func (bc *baseCell) initialize() {
+func (bc *baseCell) initialize(ctx *deuron.Context) {
+	bc.ctx = ctx
 	bc.inputs = []IConnection{}
 	bc.outputs = []IConnection{}
-	bc.refractoryPeriod = deuron.SimModel.GetFloat("RefractoryPeriod")
+	bc.refractoryPeriod = ctx.Model.GetFloat("RefractoryPeriod")
 }
 
 func (bc *baseCell) AttachDendrite(den IDendrite) {
@@ -1,6 +1,7 @@
 package cell
 
-// Global ID auto incrementing
+// Global ID auto incrementing. This is the compatibility default,
+// cells within a simulation should use deuron.Context's NextID.
 var gid int
 
 func GetNextId() int {
@@ -44,6 +45,11 @@ type ICell interface {
 	Load(json interface{})
 	Store(file string)
 
+	// State captures the dynamic state, for example, traces, for a
+	// checkpoint. RestoreState is the reverse.
+	State() interface{}
+	RestoreState(state interface{})
+
 	// Properties
 	APFast() float64
 	APSlow() float64
@@ -28,6 +28,11 @@ type ICompartment interface {
 	Load(json interface{})
 
 	ToJSON() interface{}
+
+	// State captures the dynamic state, for example, traces, for a
+	// checkpoint. RestoreState is the reverse.
+	State() interface{}
+	RestoreState(state interface{})
 }
 
 type baseCompartment struct {
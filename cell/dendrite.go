@@ -21,6 +21,11 @@ type IDendrite interface {
 	Load(json interface{})
 
 	ToJSON() interface{}
+
+	// State captures the dynamic state, for example, traces, for a
+	// checkpoint. RestoreState is the reverse.
+	State() interface{}
+	RestoreState(state interface{})
 }
 
 type baseDendrite struct {
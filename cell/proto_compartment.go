@@ -90,3 +90,34 @@ func (c *ProtoCompartment) ToJSON() interface{} {
 
 	return m
 }
+
+func (c *ProtoCompartment) State() interface{} {
+	a := make([]interface{}, c.synapses.Size())
+
+	it := c.synapses.Iterator()
+	ind := 0
+	for it.Next() {
+		synapse := it.Value().(ISynapse)
+		a[ind] = synapse.State()
+		ind++
+	}
+
+	m := map[string]interface{}{
+		"Synapses": a,
+	}
+
+	return m
+}
+
+func (c *ProtoCompartment) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
+	synsArr := jmap["Synapses"].([]interface{})
+
+	it := c.synapses.Iterator()
+	i := 0
+	for it.Next() {
+		synapse := it.Value().(ISynapse)
+		synapse.RestoreState(synsArr[i])
+		i++
+	}
+}
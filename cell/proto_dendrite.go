@@ -11,10 +11,13 @@ type ProtoDendrite struct {
 	baseDendrite
 
 	neuron ICell
+
+	ctx *deuron.Context
 }
 
-func NewProtoDendrite(cell ICell) IDendrite {
+func NewProtoDendrite(cell ICell, ctx *deuron.Context) IDendrite {
 	n := new(ProtoDendrite)
+	n.ctx = ctx
 
 	// Bidirectional associations
 	n.neuron = cell
@@ -86,7 +89,7 @@ func (d *ProtoDendrite) Load(json interface{}) {
 	d.length = dendrites["length"].(float64)
 	d.taoEff = dendrites["taoEff"].(float64)
 
-	m := deuron.SimModel
+	m := d.ctx.Model
 
 	m.SetFloat("length", d.length)
 	m.SetFloat("taoEff", d.taoEff)
@@ -118,3 +121,40 @@ func (d *ProtoDendrite) ToJSON() interface{} {
 
 	return m
 }
+
+func (d *ProtoDendrite) State() interface{} {
+	a := make([]interface{}, d.compartments.Size())
+
+	it := d.compartments.Iterator()
+	ind := 0
+	for it.Next() {
+		comp := it.Value().(ICompartment)
+		a[ind] = comp.State()
+		ind++
+	}
+
+	m := map[string]interface{}{
+		"length":       d.length,
+		"taoEff":       d.taoEff,
+		"Compartments": a,
+	}
+
+	return m
+}
+
+func (d *ProtoDendrite) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
+
+	d.length = jmap["length"].(float64)
+	d.taoEff = jmap["taoEff"].(float64)
+
+	compartments := jmap["Compartments"].([]interface{})
+
+	it := d.compartments.Iterator()
+	i := 0
+	for it.Next() {
+		comp := it.Value().(ICompartment)
+		comp.RestoreState(compartments[i])
+		i++
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"strconv"
 
 	"github.com/wdevore/Deuron5/deuron"
-	"github.com/wdevore/Deuron5/simulation/samples"
 )
 
 const (
@@ -63,14 +62,17 @@ type ProtoNeuron struct {
 	ntaoJ         float64
 	efficacyTrace float64
 
+	// Fire on the next Integrate whatever the psp, see Force.
+	forced bool
+
 	// -----------------------------------
 	// Fall off
 	// -----------------------------------
 }
 
-func NewProtoNeuron() ICell {
+func NewProtoNeuron(ctx *deuron.Context) ICell {
 	n := new(ProtoNeuron)
-	n.baseCell.initialize()
+	n.baseCell.initialize(ctx)
 
 	n.Reset()
 
@@ -100,6 +102,7 @@ func (n *ProtoNeuron) Reset() {
 	n.output = 0.0
 	n.prevOutput = 0.0
 	n.efficacyTrace = 0.0
+	n.forced = false
 	if n.dendrite != nil {
 		n.dendrite.Reset()
 	}
@@ -108,14 +111,14 @@ func (n *ProtoNeuron) Reset() {
 func (n *ProtoNeuron) Integrate(t float64) float64 {
 	dt := t - n.preT
 
-	samples.Sim.NeuronDtSamples.Put(t, dt, n.id, 0)
+	n.ctx.Samples.NeuronDtSamples.Put(t, dt, n.id, 0)
 
 	n.efficacyTrace = n.efficacy(dt, n.ntaoJ)
 
 	// Pass the current AP trace and the current and previous spike state
 	// contained in the cell.
 	psp := n.dendrite.Integrate(t, n)
-	samples.Sim.NeuronPspSamples.Put(t, psp, n.id, 0)
+	n.ctx.Samples.NeuronPspSamples.Put(t, psp, n.id, 0)
 
 	n.prevOutput = n.output
 
@@ -134,7 +137,7 @@ func (n *ProtoNeuron) Integrate(t float64) float64 {
 			n.refractoryCnt++
 		}
 	} else {
-		if psp > n.threshold {
+		if psp > n.threshold || n.forced {
 			// An action potential just occurred.
 
 			// TODO Handle depolarization
@@ -158,18 +161,26 @@ func (n *ProtoNeuron) Integrate(t float64) float64 {
 		}
 	}
 
+	n.forced = false
+
 	// Prior is for triplet
 	n.apSlowPrior = n.apSlow
 
 	n.apFast = n.nFastSurge * math.Exp(-dt/n.ntao)
 	n.apSlow = n.nSlowSurge * math.Exp(-dt/n.ntaoS)
 
-	samples.Sim.NeuronAPSamples.Put(t, n.apFast, n.id, 0)
-	samples.Sim.NeuronAPSlowSamples.Put(t, n.apSlow, n.id, 0)
+	n.ctx.Samples.NeuronAPSamples.Put(t, n.apFast, n.id, 0)
+	n.ctx.Samples.NeuronAPSlowSamples.Put(t, n.apSlow, n.id, 0)
 
 	return n.output
 }
 
+// Force makes the neuron fire on its next Integrate, for example, to pair
+// a post spike with a pre spike. It doesn't fire while refractory.
+func (n *ProtoNeuron) Force() {
+	n.forced = true
+}
+
 // This is a time based property NOT distance.
 // Each spike of the neuron i sets the post spike efficacy j to 0
 // whereafter it recovers exponentially to 1 with a time constant toaI.
@@ -247,7 +258,7 @@ func (n *ProtoNeuron) Load(json interface{}) {
 	n.refractoryPeriod = jmap["RefractoryPeriod"].(float64)
 	n.APMax = jmap["APMax"].(float64)
 
-	m := deuron.SimModel
+	m := n.ctx.Model
 
 	m.SetFloat("threshold", n.threshold)
 	m.SetFloat("RefractoryPeriod", n.refractoryPeriod)
@@ -273,10 +284,71 @@ func (n *ProtoNeuron) ToJSON() interface{} {
 		"nSlowSurge":       n.nInitialSlowSurge,
 		"RefractoryPeriod": n.refractoryPeriod,
 		"APMax":            n.APMax,
-		"wMin":             deuron.SimModel.GetFloat("weightMin"),
-		"wMax":             deuron.SimModel.GetFloat("weightMax"),
+		"wMin":             n.ctx.Model.GetFloat("weightMin"),
+		"wMax":             n.ctx.Model.GetFloat("weightMax"),
 		"Dendrites":        n.dendrite.ToJSON(),
 	}
 
 	return m
 }
+
+func (n *ProtoNeuron) State() interface{} {
+	m := map[string]interface{}{
+		"output":            n.output,
+		"prevOutput":        n.prevOutput,
+		"refractoryPeriod":  n.refractoryPeriod,
+		"refractoryCnt":     n.refractoryCnt,
+		"refractoryState":   n.refractoryState,
+		"threshold":         n.threshold,
+		"apFast":            n.apFast,
+		"apSlow":            n.apSlow,
+		"apSlowPrior":       n.apSlowPrior,
+		"APt":               n.APt,
+		"preAPt":            n.preAPt,
+		"APMax":             n.APMax,
+		"ntao":              n.ntao,
+		"ntaoS":             n.ntaoS,
+		"nFastSurge":        n.nFastSurge,
+		"nDynFastSurge":     n.nDynFastSurge,
+		"nInitialFastSurge": n.nInitialFastSurge,
+		"nSlowSurge":        n.nSlowSurge,
+		"nDynSlowSurge":     n.nDynSlowSurge,
+		"nInitialSlowSurge": n.nInitialSlowSurge,
+		"preT":              n.preT,
+		"ntaoJ":             n.ntaoJ,
+		"efficacyTrace":     n.efficacyTrace,
+		"Dendrite":          n.dendrite.State(),
+	}
+
+	return m
+}
+
+func (n *ProtoNeuron) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
+
+	n.output = jmap["output"].(float64)
+	n.prevOutput = jmap["prevOutput"].(float64)
+	n.refractoryPeriod = jmap["refractoryPeriod"].(float64)
+	n.refractoryCnt = jmap["refractoryCnt"].(float64)
+	n.refractoryState = jmap["refractoryState"].(bool)
+	n.threshold = jmap["threshold"].(float64)
+	n.apFast = jmap["apFast"].(float64)
+	n.apSlow = jmap["apSlow"].(float64)
+	n.apSlowPrior = jmap["apSlowPrior"].(float64)
+	n.APt = jmap["APt"].(float64)
+	n.preAPt = jmap["preAPt"].(float64)
+	n.APMax = jmap["APMax"].(float64)
+	n.ntao = jmap["ntao"].(float64)
+	n.ntaoS = jmap["ntaoS"].(float64)
+	n.nFastSurge = jmap["nFastSurge"].(float64)
+	n.nDynFastSurge = jmap["nDynFastSurge"].(float64)
+	n.nInitialFastSurge = jmap["nInitialFastSurge"].(float64)
+	n.nSlowSurge = jmap["nSlowSurge"].(float64)
+	n.nDynSlowSurge = jmap["nDynSlowSurge"].(float64)
+	n.nInitialSlowSurge = jmap["nInitialSlowSurge"].(float64)
+	n.preT = jmap["preT"].(float64)
+	n.ntaoJ = jmap["ntaoJ"].(float64)
+	n.efficacyTrace = jmap["efficacyTrace"].(float64)
+
+	n.dendrite.RestoreState(jmap["Dendrite"])
+}
@@ -99,10 +99,22 @@ type ProtoSynapse struct {
 	// -----------------------------------
 	distanceEfficacy float64
 	distance         float64
+
+	// While frozen the weight doesn't change, for example, during a
+	// test phase.
+	frozen bool
+
+	// The simulation this synapse belongs to.
+	ctx *deuron.Context
+
+	// This synapse's own generator.
+	ran *deuron.Random
 }
 
-func NewProtoSynapse(comp ICompartment, synType SynapseType, id int, weightSeed int64) ISynapse {
+func NewProtoSynapse(comp ICompartment, synType SynapseType, id int, seed int64, ctx *deuron.Context) ISynapse {
 	n := new(ProtoSynapse)
+	n.ctx = ctx
+	n.ran = deuron.NewRandom(seed)
 	n.comp = comp
 	n.synType = synType
 	n.SetId(id)
@@ -113,8 +125,7 @@ func NewProtoSynapse(comp ICompartment, synType SynapseType, id int, weightSeed
 	n.baseSynapse.initialize()
 
 	// Random weight [Wmin -> Wmax]
-	// ran := rand.New(rand.NewSource(weightSeed))
-	// n.w = n.wMin + n.wMax*ran.Float64()
+	// n.w = n.wMin + n.wMax*n.ran.Uniform()
 
 	return n
 }
@@ -125,7 +136,7 @@ func (n *ProtoSynapse) Reset() {
 	n.psp = 0
 	n.preT = 0
 	// Reset weights back to initial values.
-	n.wMax = deuron.SimModel.GetFloat("weightMax")
+	n.wMax = n.ctx.Model.GetFloat("weightMax")
 	n.w = n.wMax / 2
 }
 
@@ -153,7 +164,7 @@ func (n *ProtoSynapse) pair_integrate(t, apFast, somaOutput float64) float64 {
 	// psp decreases asymtotically to zero.
 	dt := t - n.preT
 
-	samples.Sim.DtSamples.Put(t, dt, n.id, 0)
+	n.ctx.Samples.DtSamples.Put(t, dt, n.id, 0)
 
 	// Sample the connection to this synapse. The connection will have already
 	// "merged" all traffic through to the connection's output.
@@ -167,7 +178,9 @@ func (n *ProtoSynapse) pair_integrate(t, apFast, somaOutput float64) float64 {
 
 		// Depression
 		// Read post trace and adjust weight accordingly.
-		n.w = math.Max(n.w-apFast, n.wMin)
+		if !n.frozen {
+			n.w = math.Max(n.w-apFast, n.wMin)
+		}
 
 		n.preT = t
 		dt = 0.0
@@ -180,25 +193,25 @@ func (n *ProtoSynapse) pair_integrate(t, apFast, somaOutput float64) float64 {
 		}
 	}
 
-	samples.Sim.SurgeSamples.Put(t, n.surge, n.id, 0)
+	n.ctx.Samples.SurgeSamples.Put(t, n.surge, n.id, 0)
 
 	// If an AP occurred we read the current n.psp value and add it
 	// to the "w"
-	if somaOutput == 1.0 {
+	if somaOutput == 1.0 && !n.frozen {
 		// Potentiation
 		// Read pre trace (aka psp) and adjust weight accordingly.
 		n.w = math.Min(n.w+n.psp, n.wMax)
 	}
 
-	samples.Sim.WeightSamples.Put(t, n.w, n.id, 0)
+	n.ctx.Samples.WeightSamples.Put(t, n.w, n.id, n.weightKey())
 
 	// Return the "value" of this synapse for this "t"
 	if !n.IsExcititory() {
-		samples.Sim.PspSamples.Put(t, -n.psp, n.id, 0)
+		n.ctx.Samples.PspSamples.Put(t, -n.psp, n.id, 0)
 		return -n.psp * n.w
 	}
 
-	samples.Sim.PspSamples.Put(t, n.psp, n.id, 0)
+	n.ctx.Samples.PspSamples.Put(t, n.psp, n.id, 0)
 
 	return n.psp * n.w
 }
@@ -212,7 +225,7 @@ func (n *ProtoSynapse) triplet_integrate(t float64, cell ICell) float64 {
 	// psp decreases asymtotically to zero.
 	dt := t - n.preT
 
-	samples.Sim.DtSamples.Put(t, dt, n.id, 0)
+	n.ctx.Samples.DtSamples.Put(t, dt, n.id, 0)
 
 	// Sample the connection to this synapse. The connection will have already
 	// "merged" all traffic through to the connection's output.
@@ -252,7 +265,7 @@ func (n *ProtoSynapse) triplet_integrate(t float64, cell ICell) float64 {
 		n.psp = n.surge * math.Exp(-dt/n.taoN)
 	}
 
-	samples.Sim.SurgeSamples.Put(t, n.surge, n.id, 0)
+	n.ctx.Samples.SurgeSamples.Put(t, n.surge, n.id, 0)
 
 	// If an AP occurred we read the current n.psp value and add it
 	// to the "w"
@@ -267,23 +280,31 @@ func (n *ProtoSynapse) triplet_integrate(t float64, cell ICell) float64 {
 	}
 
 	// Finally update the weight.
-	if updateWeight {
+	if updateWeight && !n.frozen {
 		n.w = math.Max(math.Min(n.w+dwP-dwD, n.wMax), n.wMin)
 	}
 
-	samples.Sim.WeightSamples.Put(t, n.w, n.id, 0)
+	n.ctx.Samples.WeightSamples.Put(t, n.w, n.id, n.weightKey())
 
 	// Return the "value" of this synapse for this "t"
 	if !n.IsExcititory() {
-		samples.Sim.PspSamples.Put(t, -n.psp, n.id, 0)
+		n.ctx.Samples.PspSamples.Put(t, -n.psp, n.id, 0)
 		return -n.psp * n.w
 	}
 
-	samples.Sim.PspSamples.Put(t, n.psp, n.id, 0)
+	n.ctx.Samples.PspSamples.Put(t, n.psp, n.id, 0)
 
 	return n.psp * n.w
 }
 
+// weightKey marks weight samples with the synapse's type.
+func (n *ProtoSynapse) weightKey() int {
+	if n.IsExcititory() {
+		return samples.ExcititoryKey
+	}
+	return samples.InhibitoryKey
+}
+
 // Each spike of pre-synaptic neuron j sets the presynaptic spike
 // efficacy j to 0
 // whereafter it recovers exponentially to 1 with a time constant
@@ -342,6 +363,11 @@ func (n *ProtoSynapse) SetField(field, value string) {
 	case "distance":
 		n.distance, _ = strconv.ParseFloat(value, 64)
 		break
+	case "learning":
+		// 0 freezes the weight, anything else lets it learn.
+		learning, _ := strconv.ParseFloat(value, 64)
+		n.frozen = learning == 0.0
+		break
 	}
 }
 
@@ -382,10 +408,10 @@ func (n *ProtoSynapse) Load(json interface{}) {
 
 	n.w = n.jmap["w"].(float64)
 
-	n.wMax = deuron.SimModel.GetFloat("weightMax")
-	n.wMin = deuron.SimModel.GetFloat("weightMin")
+	n.wMax = n.ctx.Model.GetFloat("weightMax")
+	n.wMin = n.ctx.Model.GetFloat("weightMin")
 
-	m := deuron.SimModel
+	m := n.ctx.Model
 
 	m.SetFloat("amb", n.amb)
 	m.SetFloat("ama", n.ama)
@@ -419,3 +445,66 @@ func (n *ProtoSynapse) ToJSON() interface{} {
 
 	return m
 }
+
+func (n *ProtoSynapse) State() interface{} {
+	m := map[string]interface{}{
+		"w":                n.w,
+		"wi":               n.wi,
+		"wMax":             n.wMax,
+		"wMin":             n.wMin,
+		"amb":              n.amb,
+		"ama":              n.ama,
+		"tsw":              n.tsw,
+		"surge":            n.surge,
+		"preT":             n.preT,
+		"psp":              n.psp,
+		"taoP":             n.taoP,
+		"taoN":             n.taoN,
+		"tao":              n.tao,
+		"mu":               n.mu,
+		"lambda":           n.lambda,
+		"alpha":            n.alpha,
+		"taoI":             n.taoI,
+		"prevEffTrace":     n.prevEffTrace,
+		"learningRateSlow": n.learningRateSlow,
+		"learningRateFast": n.learningRateFast,
+		"distanceEfficacy": n.distanceEfficacy,
+		"distance":         n.distance,
+		"frozen":           n.frozen,
+		"Random":           n.ran.State(),
+	}
+
+	return m
+}
+
+func (n *ProtoSynapse) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
+
+	n.w = jmap["w"].(float64)
+	n.wi = jmap["wi"].(float64)
+	n.wMax = jmap["wMax"].(float64)
+	n.wMin = jmap["wMin"].(float64)
+	n.amb = jmap["amb"].(float64)
+	n.ama = jmap["ama"].(float64)
+	n.tsw = jmap["tsw"].(float64)
+	n.surge = jmap["surge"].(float64)
+	n.preT = jmap["preT"].(float64)
+	n.psp = jmap["psp"].(float64)
+	n.taoP = jmap["taoP"].(float64)
+	n.taoN = jmap["taoN"].(float64)
+	n.tao = jmap["tao"].(float64)
+	n.mu = jmap["mu"].(float64)
+	n.lambda = jmap["lambda"].(float64)
+	n.alpha = jmap["alpha"].(float64)
+	n.taoI = jmap["taoI"].(float64)
+	n.prevEffTrace = jmap["prevEffTrace"].(float64)
+	n.learningRateSlow = jmap["learningRateSlow"].(float64)
+	n.learningRateFast = jmap["learningRateFast"].(float64)
+	n.distanceEfficacy = jmap["distanceEfficacy"].(float64)
+	n.distance = jmap["distance"].(float64)
+	if frozen, ok := jmap["frozen"]; ok {
+		n.frozen = frozen.(bool)
+	}
+
+	n.ran.RestoreState(jmap["Random"])
+}
@@ -39,4 +39,9 @@ type IPatternStream interface {
 	// Step moves the stream to it next value
 	// returns true if patten complete during this step.
 	Step() bool
+
+	// State captures the stream's position and RNG for a checkpoint.
+	// RestoreState is the reverse.
+	State() interface{}
+	RestoreState(state interface{})
 }
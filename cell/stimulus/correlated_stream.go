@@ -0,0 +1,268 @@
+package stimulus
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/wdevore/Deuron5/cell"
+	"github.com/wdevore/Deuron5/deuron"
+)
+
+// CorrelatedGroup produces lanes that share spike timing. A poisson
+// mother process fires at Rate/c and each lane copies each mother spike
+// with probability c, so every lane fires at Rate and any two lanes have
+// a correlation coefficient of c. Copied spikes are moved by up to
+// +/- jitter ms. To stay causal every lane is also delayed by jitter ms.
+type CorrelatedGroup struct {
+	ran *deuron.Random
+
+	// Random seed
+	seed int64
+
+	rate        float64 // spikes/ms of each lane
+	correlation float64
+	jitter      float64 // ms
+	dt          float64 // ms
+
+	lanes []*CorrelatedStream
+
+	// Steps generated so far
+	step int
+
+	// Time of the next mother spike
+	nextT float64
+}
+
+// NewCorrelatedGroup creates a group without lanes. correlation must be
+// within (0, 1].
+func NewCorrelatedGroup(seed int64, rate, correlation, jitter, dt float64) (*CorrelatedGroup, error) {
+	if rate <= 0 {
+		return nil, errors.New("correlated group: rate must be > 0")
+	}
+	if correlation <= 0 || correlation > 1 {
+		return nil, errors.New("correlated group: correlation must be within (0, 1]")
+	}
+	if jitter < 0 {
+		return nil, errors.New("correlated group: jitter can't be negative")
+	}
+
+	g := new(CorrelatedGroup)
+	g.seed = seed
+	g.ran = deuron.NewRandom(seed)
+	g.rate = rate
+	g.correlation = correlation
+	g.jitter = jitter
+	g.dt = dt
+
+	g.Reset()
+
+	return g, nil
+}
+
+// NewLane adds a lane to the group.
+func (g *CorrelatedGroup) NewLane() IPatternStream {
+	l := new(CorrelatedStream)
+	l.baseInitialize()
+	l.group = g
+	g.lanes = append(g.lanes, l)
+	return l
+}
+
+func (g *CorrelatedGroup) Lanes() []*CorrelatedStream {
+	return g.lanes
+}
+
+func (g *CorrelatedGroup) motherISI() float64 {
+	return exponential(g.ran) * g.correlation / g.rate
+}
+
+// Reset restarts the mother process and clears every lane.
+func (g *CorrelatedGroup) Reset() {
+	g.ran.Seed(g.seed)
+	g.step = 0
+	g.nextT = g.motherISI()
+
+	for _, l := range g.lanes {
+		l.clear()
+	}
+}
+
+// advance generates mother spikes, and the lanes' copies of them, up to
+// the end of the given step. Lanes step independently so the first lane
+// to reach a step generates it for all of them.
+func (g *CorrelatedGroup) advance(step int) {
+	for g.step < step {
+		g.step++
+		end := float64(g.step) * g.dt
+
+		for g.nextT < end {
+			for _, l := range g.lanes {
+				if g.ran.Uniform() < g.correlation {
+					t := g.nextT + g.jitter
+					if g.jitter > 0 {
+						t += g.jitter * (2.0*g.ran.Uniform() - 1.0)
+					}
+					l.schedule(t)
+				}
+			}
+			g.nextT += g.motherISI()
+		}
+	}
+}
+
+func (g *CorrelatedGroup) State() interface{} {
+	return map[string]interface{}{
+		"step":   g.step,
+		"nextT":  g.nextT,
+		"Random": g.ran.State(),
+	}
+}
+
+func (g *CorrelatedGroup) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
+
+	g.step = int(jmap["step"].(float64))
+	g.nextT = jmap["nextT"].(float64)
+
+	g.ran.RestoreState(jmap["Random"])
+}
+
+// CorrelatedStream is one lane of a CorrelatedGroup.
+type CorrelatedStream struct {
+	basePatternStream
+
+	group *CorrelatedGroup
+
+	step int
+
+	// Spike times, in order, that haven't been output yet.
+	pending []float64
+}
+
+func (ss *CorrelatedStream) clear() {
+	ss.step = 0
+	ss.value = 0
+	ss.pending = ss.pending[:0]
+}
+
+func (ss *CorrelatedStream) schedule(t float64) {
+	i := len(ss.pending)
+	ss.pending = append(ss.pending, t)
+	for i > 0 && ss.pending[i-1] > t {
+		ss.pending[i] = ss.pending[i-1]
+		i--
+	}
+	ss.pending[i] = t
+}
+
+// ----------------------------------------------
+// IPatternStream methods
+// ----------------------------------------------
+func (ss *CorrelatedStream) EnableAutoReset() {
+	// Not applicable
+}
+
+// Reset resets the whole group.
+func (ss *CorrelatedStream) Reset() {
+	ss.group.Reset()
+}
+
+func (ss *CorrelatedStream) Step() bool {
+	ss.step++
+	ss.group.advance(ss.step)
+
+	end := float64(ss.step) * ss.group.dt
+
+	ss.value = 0
+	n := 0
+	for n < len(ss.pending) && ss.pending[n] < end {
+		ss.value = 1
+		n++
+	}
+	ss.pending = ss.pending[n:]
+
+	it := ss.cons.Iterator()
+	for it.Next() {
+		conn := it.Value().(cell.IConnection)
+		conn.Input(ss.value)
+	}
+
+	return false
+}
+
+func (ss *CorrelatedStream) IsComplete() bool {
+	return false // This type of stream never completes
+}
+
+// ----------------------------------------------
+// IBitStream methods
+// ----------------------------------------------
+
+func (ss *CorrelatedStream) Input(v int) {
+	// Not applicable.
+}
+
+func (ss *CorrelatedStream) Output() int {
+	return ss.value
+}
+
+// State includes the group's state. Restoring any lane restores the
+// group, which is the same for every lane.
+func (ss *CorrelatedStream) State() interface{} {
+	pending := make([]interface{}, len(ss.pending))
+	for i, t := range ss.pending {
+		pending[i] = t
+	}
+
+	m := map[string]interface{}{
+		"step":    ss.step,
+		"value":   ss.value,
+		"pending": pending,
+		"Group":   ss.group.State(),
+	}
+
+	return m
+}
+
+func (ss *CorrelatedStream) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
+
+	ss.step = int(jmap["step"].(float64))
+	ss.value = int(jmap["value"].(float64))
+
+	ss.pending = ss.pending[:0]
+	for _, t := range jmap["pending"].([]interface{}) {
+		ss.pending = append(ss.pending, t.(float64))
+	}
+
+	ss.group.RestoreState(jmap["Group"])
+}
+
+// CorrelationSpec is a group of correlated synapses in the stimulus
+// settings' Noise section.
+type CorrelationSpec struct {
+	Synapses    []int
+	Rate        float64 // spikes/ms
+	Correlation float64
+	Jitter      float64 // ms
+}
+
+// Build creates the group and a lane for each synapse, keyed by synapse.
+// Synapses outside [0, synCnt) are ignored, they'd never be stepped.
+func (cs *CorrelationSpec) Build(seed int64, dt float64, synCnt int) (map[int]IPatternStream, error) {
+	g, err := NewCorrelatedGroup(seed, cs.Rate, cs.Correlation, cs.Jitter, dt)
+	if err != nil {
+		return nil, err
+	}
+
+	lanes := map[int]IPatternStream{}
+	for _, syn := range cs.Synapses {
+		if syn < 0 || syn >= synCnt {
+			fmt.Printf("Correlated lane for synapse (%d) ignored, there are only %d synapses\n", syn, synCnt)
+			continue
+		}
+		lanes[syn] = g.NewLane()
+	}
+
+	return lanes, nil
+}
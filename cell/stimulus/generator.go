@@ -0,0 +1,266 @@
+package stimulus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/wdevore/Deuron5/deuron"
+)
+
+// GeneratorSpec describes a set of random patterns to embed in the
+// noise. Lane i of every pattern feeds synapse i.
+type GeneratorSpec struct {
+	Name string
+
+	// Lanes per pattern.
+	Lanes int
+	// Length of each lane before it is expanded by the StimulusScaler.
+	Length int
+	// Spikes in each lane.
+	SpikesPerLane int
+	// Number of distinct patterns.
+	Patterns int
+	// Fraction [0, 1] of each pattern's lanes that repeat the first
+	// pattern's lanes.
+	Overlap float64
+
+	Seed int64
+
+	// Synapses in the matching settings file. 0 means Lanes.
+	Synapses int
+
+	// Milliseconds from one pattern's onset to the next's, the patterns
+	// take turns. 0 means twice a pattern's length once expanded by the
+	// settings' StimulusScaler.
+	Interval int
+}
+
+// The StimulusScaler of the generated settings.
+const generatedScaler = 9.0
+
+// expandedLength is a lane's length in the sim.
+func (gs *GeneratorSpec) expandedLength() int {
+	return int(math.Round(float64(gs.Length) * (generatedScaler + 1)))
+}
+
+// interval is the onset to onset time of the patterns.
+func (gs *GeneratorSpec) interval() int {
+	if gs.Interval == 0 {
+		return 2 * gs.expandedLength()
+	}
+	return gs.Interval
+}
+
+// Validate checks the spec makes sense.
+func (gs *GeneratorSpec) Validate() error {
+	if gs.Name == "" {
+		return errors.New("generator: a name is required")
+	}
+	if gs.Lanes < 1 || gs.Length < 1 || gs.Patterns < 1 {
+		return errors.New("generator: lanes, length and patterns must be at least 1")
+	}
+	if gs.SpikesPerLane < 0 || gs.SpikesPerLane > gs.Length {
+		return fmt.Errorf("generator: spikes per lane (%d) must be within [0, %d]", gs.SpikesPerLane, gs.Length)
+	}
+	if gs.Overlap < 0 || gs.Overlap > 1 {
+		return fmt.Errorf("generator: overlap (%f) must be within [0, 1]", gs.Overlap)
+	}
+	if gs.Synapses != 0 && gs.Synapses < gs.Lanes {
+		return fmt.Errorf("generator: synapses (%d) can't be less than lanes (%d)", gs.Synapses, gs.Lanes)
+	}
+	if gs.Interval != 0 && gs.Interval <= gs.expandedLength() {
+		return fmt.Errorf("generator: interval (%d) must be longer than an expanded pattern (%d)", gs.Interval, gs.expandedLength())
+	}
+	return nil
+}
+
+// Generate creates the patterns. The same spec always gives the same
+// patterns. They're scheduled one after the other, Interval apart, so
+// that they are never presented on top of each other.
+func Generate(spec *GeneratorSpec) (*StimulusFile, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	ran := deuron.NewRandom(spec.Seed)
+
+	sf := new(StimulusFile)
+	sf.Version = StimulusVersion
+	sf.Name = spec.Name
+	interval := spec.interval()
+	sf.Description = fmt.Sprintf("Generated: lanes %d, length %d, spikes/lane %d, patterns %d, overlap %0.2f, seed %d, interval %d",
+		spec.Lanes, spec.Length, spec.SpikesPerLane, spec.Patterns, spec.Overlap, spec.Seed, interval)
+
+	shared := int(math.Round(spec.Overlap * float64(spec.Lanes)))
+
+	var first *PatternSpec
+
+	for p := 0; p < spec.Patterns; p++ {
+		one := 1.0
+		pattern := &PatternSpec{
+			Name:        fmt.Sprintf("%d", p+1),
+			Timing:      "scheduled",
+			Schedule:    []int{p * interval},
+			Repeat:      spec.Patterns * interval,
+			Probability: &one,
+		}
+
+		// Which lanes repeat the first pattern.
+		repeat := make([]bool, spec.Lanes)
+		if first != nil {
+			for _, l := range permutation(ran, spec.Lanes)[:shared] {
+				repeat[l] = true
+			}
+		}
+
+		for l := 0; l < spec.Lanes; l++ {
+			spikes := ""
+			if repeat[l] {
+				spikes = first.Lanes[l].Spikes
+			} else {
+				spikes = randomLane(ran, spec.Length, spec.SpikesPerLane)
+			}
+
+			pattern.Lanes = append(pattern.Lanes, &LaneSpec{
+				Name:    fmt.Sprintf("%d", l),
+				Synapse: l,
+				Spikes:  spikes,
+			})
+		}
+
+		if first == nil {
+			first = pattern
+		}
+
+		sf.Patterns = append(sf.Patterns, pattern)
+	}
+
+	return sf, nil
+}
+
+// randomLane places spikes at distinct random positions.
+func randomLane(ran *deuron.Random, length, spikes int) string {
+	lane := []byte(strings.Repeat(".", length))
+	for _, i := range permutation(ran, length)[:spikes] {
+		lane[i] = '|'
+	}
+	return string(lane)
+}
+
+// permutation is a Fisher-Yates shuffle of [0, n).
+func permutation(ran *deuron.Random, n int) []int {
+	p := make([]int, n)
+	for i := range p {
+		p[i] = i
+	}
+	for i := n - 1; i > 0; i-- {
+		j := int(ran.Int63() % int64(i+1))
+		p[i], p[j] = p[j], p[i]
+	}
+	return p
+}
+
+// DefaultSettings is the stimulus settings file (stimulus/<name>.json)
+// with every synapse given the default parameters.
+func DefaultSettings(synapses int) map[string]interface{} {
+	syns := make([]interface{}, synapses)
+	for i := range syns {
+		syns[i] = map[string]interface{}{
+			"id":               i,
+			"w":                5.0,
+			"taoP":             17.0,
+			"taoN":             33.0,
+			"taoI":             10.0,
+			"distance":         1.0,
+			"ama":              1.2,
+			"amb":              10.8,
+			"mu":               0.32,
+			"lambda":           1.0,
+			"alpha":            1.05,
+			"learningRateSlow": 0.21,
+			"learningRateFast": 0.32,
+		}
+	}
+
+	neuron := map[string]interface{}{
+		"id":               0,
+		"Threshold":        39.0,
+		"ntao":             10.0,
+		"ntaoS":            50.0,
+		"ntaoJ":            10.0,
+		"nFastSurge":       8.0,
+		"nSlowSurge":       8.0,
+		"RefractoryPeriod": 3.0,
+		"APMax":            20.0,
+		"wMin":             0.0,
+		"wMax":             10.0,
+		"Dendrites": map[string]interface{}{
+			"id":     0,
+			"length": 1.0,
+			"taoEff": 10.0,
+			"Compartments": []interface{}{
+				map[string]interface{}{
+					"id":       0,
+					"Synapses": syns,
+				},
+			},
+		},
+	}
+
+	return map[string]interface{}{
+		"Firing_Rate":            0.005,
+		"Hertz":                  20.0,
+		"Poisson_Pattern_max":    300.0,
+		"Poisson_Pattern_min":    50.0,
+		"Poisson_Pattern_spread": 50.0,
+		"RefractoryPeriod":       3.0,
+		"StimulusScaler":         generatedScaler,
+		"threshold":              39.0,
+		"Neuron":                 neuron,
+	}
+}
+
+// Save writes the stimulus in the versioned json format.
+func (sf *StimulusFile) Save(fileName string) error {
+	return saveJSON(fileName, sf)
+}
+
+// WriteGenerated generates the patterns and writes dir/<name>.patterns.json
+// and the matching dir/<name>.json settings.
+func WriteGenerated(dir string, spec *GeneratorSpec) (*StimulusFile, error) {
+	sf, err := Generate(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sf.Save(filepath.Join(dir, spec.Name+".patterns.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	synapses := spec.Synapses
+	if synapses == 0 {
+		synapses = spec.Lanes
+	}
+
+	err = saveJSON(filepath.Join(dir, spec.Name+".json"), DefaultSettings(synapses))
+	if err != nil {
+		return nil, err
+	}
+
+	return sf, nil
+}
+
+func saveJSON(fileName string, v interface{}) error {
+	indentedJSON, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fileName, indentedJSON, 0644)
+}
@@ -0,0 +1,146 @@
+package stimulus
+
+import (
+	"math"
+
+	"github.com/wdevore/Deuron5/cell"
+	"github.com/wdevore/Deuron5/deuron"
+)
+
+// InhomogeneousPoissonStream generates poisson noise whose rate follows
+// an IRate. Spike times are drawn by thinning (Lewis and Shedler):
+// candidates come from a homogeneous process at the rate's Max and each
+// is kept with probability Rate(t)/Max. A step outputs a spike if any
+// candidate in it was kept, a step of dt ms having about Rate(t)*dt
+// expected spikes.
+type InhomogeneousPoissonStream struct {
+	basePatternStream
+
+	ran *deuron.Random
+
+	// Random seed
+	seed int64
+
+	rate IRate
+
+	// Step size in ms.
+	dt float64
+
+	// Steps since reset
+	t int
+
+	// Time of the next candidate spike.
+	nextT float64
+}
+
+// NewInhomogeneousPoissonStream creates a stream following rate that
+// steps dt milliseconds at a time.
+func NewInhomogeneousPoissonStream(seed int64, rate IRate, dt float64) IPatternStream {
+	s := new(InhomogeneousPoissonStream)
+	s.baseInitialize()
+
+	s.seed = seed
+	s.ran = deuron.NewRandom(seed)
+	s.rate = rate
+	s.dt = dt
+
+	s.Reset()
+
+	return s
+}
+
+func (ss *InhomogeneousPoissonStream) Rate() IRate {
+	return ss.rate
+}
+
+func (ss *InhomogeneousPoissonStream) candidate(from float64) float64 {
+	max := ss.rate.Max()
+	if max <= 0.0 {
+		return math.Inf(1)
+	}
+	return from - math.Log(1.0-ss.ran.Uniform())/max
+}
+
+// ----------------------------------------------
+// IPatternStream methods
+// ----------------------------------------------
+func (ss *InhomogeneousPoissonStream) EnableAutoReset() {
+	// Not applicable
+}
+
+func (ss *InhomogeneousPoissonStream) Reset() {
+	ss.ran.Seed(ss.seed)
+	ss.t = 0
+	ss.value = 0
+	ss.nextT = ss.candidate(0.0)
+}
+
+func (ss *InhomogeneousPoissonStream) Step() bool {
+	ss.value = 0
+
+	end := float64(ss.t+1) * ss.dt
+	max := ss.rate.Max()
+
+	for ss.nextT < end {
+		if ss.ran.Uniform()*max < ss.rate.Rate(ss.nextT) {
+			ss.value = 1
+		}
+		ss.nextT = ss.candidate(ss.nextT)
+	}
+
+	ss.t++
+
+	it := ss.cons.Iterator()
+	for it.Next() {
+		conn := it.Value().(cell.IConnection)
+		conn.Input(ss.value)
+	}
+
+	return false
+}
+
+func (ss *InhomogeneousPoissonStream) IsComplete() bool {
+	return false // This type of stream never completes
+}
+
+// ----------------------------------------------
+// IBitStream methods
+// ----------------------------------------------
+
+func (ss *InhomogeneousPoissonStream) Input(v int) {
+	// Not applicable.
+}
+
+func (ss *InhomogeneousPoissonStream) Output() int {
+	return ss.value
+}
+
+func (ss *InhomogeneousPoissonStream) State() interface{} {
+	// json can't hold +Inf, a zero rate has no next candidate.
+	nextT := ss.nextT
+	if math.IsInf(nextT, 1) {
+		nextT = -1.0
+	}
+
+	m := map[string]interface{}{
+		"t":      ss.t,
+		"nextT":  nextT,
+		"value":  ss.value,
+		"Random": ss.ran.State(),
+	}
+
+	return m
+}
+
+func (ss *InhomogeneousPoissonStream) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
+
+	ss.t = int(jmap["t"].(float64))
+	ss.nextT = jmap["nextT"].(float64)
+	if ss.nextT < 0.0 {
+		ss.nextT = math.Inf(1)
+	}
+	ss.value = int(jmap["value"].(float64))
+
+	ss.ran.RestoreState(jmap["Random"])
+}
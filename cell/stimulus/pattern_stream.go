@@ -1,112 +1,103 @@
 package stimulus
 
 import (
-	"fmt"
-	"strings"
-
-	sll "github.com/emirpasic/gods/lists/singlylinkedlist"
+	"math"
 )
 
-// NPatternStream N spike streams.
+// NPatternStream presents N spike streams at a fixed frequency.
 // Each stream is routed to 1 or more IConnections.
+//
+// A presentation begins every period steps. If the pattern is longer
+// than the period the next presentation begins as soon as it completes.
+//
+// |--pattern--|-----------|--pattern--|-----------|--pattern--|
+// ^                       ^
+// |  period               |
 type NPatternStream struct {
-	output byte
-
-	complete  bool
-	autoReset bool
+	basePresenter
 
-	// A collection of streams
-	patterns *sll.List
-	patItr   sll.Iterator
 	// period = 1/frequency * 1000 (ms)
 	period int // in milliseconds
 
-	// delay = period - pattern_length
-	delay    int
-	delayCnt int
+	// Steps since the current presentation began.
+	cnt int
+
+	presenting bool
 }
 
-func NewNPatternStream() *NPatternStream {
+func NewNPatternStream(seed int64) *NPatternStream {
 	s := new(NPatternStream)
-	s.autoReset = true
-	s.patterns = sll.New()
+	s.baseInitialize(seed)
+	s.period = 1
 	return s
 }
 
-// Period sets the "delay" between pattern applications. The pattern
-// period itself could be longer that the delay.
-func (nps *NPatternStream) Period(period, patternLen int) {
+// SetPeriod sets the steps from the beginning of one presentation to
+// the next.
+func (nps *NPatternStream) SetPeriod(period int) {
 	nps.period = period
-	nps.delay = nps.period - patternLen
-	nps.delayCnt = nps.delay
-
-}
-
-func (nps *NPatternStream) Add(strm IPatternStream) {
-	nps.patterns.Add(strm)
+	if nps.period < 1 {
+		nps.period = 1
+	}
 }
 
-func (nps *NPatternStream) IsComplete() bool {
-	return false
+// SetHertz sets the presentations per second.
+func (nps *NPatternStream) SetHertz(hertz float64) {
+	nps.SetPeriod(int(math.Round(1000.0 / hertz)))
 }
 
-func (nps *NPatternStream) EnableAutoReset() {
-	nps.autoReset = true
+func (nps *NPatternStream) Period() int {
+	return nps.period
 }
 
 func (nps *NPatternStream) Reset() {
-	nps.complete = false
-	nps.delayCnt = nps.delay
-	it := nps.patterns.Iterator()
-	for it.Next() {
-		stim := it.Value().(IPatternStream)
-		stim.Reset()
-	}
+	nps.ran.Seed(nps.seed)
+	nps.cnt = 0
+	nps.presenting = false
+	nps.resetStreams()
 }
 
 func (nps *NPatternStream) Step() {
-	// Step all the streams when the delay had ended.
-	// Once the pattern has completed we switch back to delay.
-	if nps.delayCnt < 0 {
-		var complete bool
-		it := nps.patterns.Iterator()
-		for it.Next() {
-			stim := it.Value().(IPatternStream)
-			complete = complete || stim.Step()
-		}
-
-		if complete {
-			// Reset for another delay period
-			nps.Reset()
-		}
-	} else {
-		nps.delayCnt--
+	if nps.cnt == 0 && !nps.presenting {
+		nps.presenting = !nps.skip()
 	}
-}
 
-func (nps *NPatternStream) Begin() bool {
-	nps.patItr = nps.patterns.Iterator()
-	return nps.patItr.First()
+	if nps.presenting && nps.stepStreams() {
+		nps.presenting = false
+		nps.resetStreams()
+	}
+
+	nps.cnt++
+
+	if nps.cnt >= nps.period && !nps.presenting {
+		nps.cnt = 0
+	}
 }
 
-func (nps *NPatternStream) Next() bool {
-	return nps.patItr.Next()
+func (nps *NPatternStream) IsPresenting() bool {
+	return nps.presenting
 }
 
-// Stream returns the next available pattern stream.Begin
-func (nps *NPatternStream) Stream() IPatternStream {
-	stream := nps.patItr.Value().(IPatternStream)
-	return stream
+func (nps *NPatternStream) State() interface{} {
+	m := map[string]interface{}{
+		"period":     nps.period,
+		"cnt":        nps.cnt,
+		"presenting": nps.presenting,
+		"Random":     nps.ran.State(),
+		"Patterns":   nps.streamsState(),
+	}
+
+	return m
 }
 
-func (nps NPatternStream) String() string {
-	var s strings.Builder
+func (nps *NPatternStream) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
 
-	it := nps.patterns.Iterator()
-	for it.Next() {
-		stim := it.Value().(*SpikeStream)
-		s.WriteString(fmt.Sprintf("%s\n", stim.String()))
-	}
+	nps.period = int(jmap["period"].(float64))
+	nps.cnt = int(jmap["cnt"].(float64))
+	nps.presenting = jmap["presenting"].(bool)
+
+	nps.ran.RestoreState(jmap["Random"])
 
-	return s.String()
+	nps.restoreStreams(jmap["Patterns"])
 }
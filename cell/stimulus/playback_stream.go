@@ -0,0 +1,309 @@
+package stimulus
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wdevore/Deuron5/cell"
+)
+
+// PlaybackStream plays back recorded spike times (ms). A recorded time r
+// is output at offset + scale*r. When looping the recording repeats
+// every period (recorded ms).
+type PlaybackStream struct {
+	basePatternStream
+
+	times []float64
+
+	scale  float64
+	offset float64 // ms
+
+	loop   bool
+	period float64 // recorded ms
+
+	// Step size in ms.
+	dt float64
+
+	step  int
+	idx   int // next spike
+	cycle int // loops completed
+
+	complete bool
+}
+
+// NewPlaybackStream plays times, which must be sorted. period is the
+// length of the recording, 0 means just past the last spike.
+func NewPlaybackStream(times []float64, period, dt float64) *PlaybackStream {
+	s := new(PlaybackStream)
+	s.baseInitialize()
+
+	s.times = times
+	s.scale = 1.0
+	s.dt = dt
+
+	s.period = period
+	if s.period <= 0 && len(times) > 0 {
+		s.period = times[len(times)-1] + dt
+	}
+
+	return s
+}
+
+// SetScale stretches (> 1) or compresses (< 1) the recording.
+func (ss *PlaybackStream) SetScale(scale float64) {
+	ss.scale = scale
+}
+
+// SetOffset delays the recording by offset ms.
+func (ss *PlaybackStream) SetOffset(offset float64) {
+	ss.offset = offset
+}
+
+// SetLoop repeats the recording rather than ending.
+func (ss *PlaybackStream) SetLoop(loop bool) {
+	ss.loop = loop
+}
+
+// Length is the played length, in ms, of one pass of the recording.
+func (ss *PlaybackStream) Length() float64 {
+	return ss.offset + ss.scale*ss.period
+}
+
+// spikeTime is when the next spike is played.
+func (ss *PlaybackStream) spikeTime() float64 {
+	return ss.offset + ss.scale*(ss.times[ss.idx]+float64(ss.cycle)*ss.period)
+}
+
+// ----------------------------------------------
+// IPatternStream methods
+// ----------------------------------------------
+func (ss *PlaybackStream) EnableAutoReset() {
+	ss.loop = true
+}
+
+func (ss *PlaybackStream) Reset() {
+	ss.step = 0
+	ss.idx = 0
+	ss.cycle = 0
+	ss.value = 0
+	ss.complete = false
+}
+
+func (ss *PlaybackStream) Step() bool {
+	ss.step++
+	end := float64(ss.step) * ss.dt
+
+	ss.value = 0
+	for ss.idx < len(ss.times) && ss.spikeTime() < end {
+		ss.value = 1
+		ss.idx++
+		if ss.idx == len(ss.times) && ss.loop && ss.scale*ss.period > 0 {
+			ss.idx = 0
+			ss.cycle++
+		}
+	}
+
+	if !ss.loop && end >= ss.Length() {
+		ss.complete = true
+	}
+
+	it := ss.cons.Iterator()
+	for it.Next() {
+		conn := it.Value().(cell.IConnection)
+		conn.Input(ss.value)
+	}
+
+	return ss.complete
+}
+
+func (ss *PlaybackStream) IsComplete() bool {
+	return ss.complete
+}
+
+// ----------------------------------------------
+// IBitStream methods
+// ----------------------------------------------
+
+func (ss *PlaybackStream) Input(v int) {
+	// Not applicable.
+}
+
+func (ss *PlaybackStream) Output() int {
+	return ss.value
+}
+
+func (ss *PlaybackStream) State() interface{} {
+	m := map[string]interface{}{
+		"step":     ss.step,
+		"idx":      ss.idx,
+		"cycle":    ss.cycle,
+		"value":    ss.value,
+		"complete": ss.complete,
+	}
+
+	return m
+}
+
+func (ss *PlaybackStream) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
+
+	ss.step = int(jmap["step"].(float64))
+	ss.idx = int(jmap["idx"].(float64))
+	ss.cycle = int(jmap["cycle"].(float64))
+	ss.value = int(jmap["value"].(float64))
+	ss.complete = jmap["complete"].(bool)
+}
+
+// LoadRecording reads spike times (ms) per channel. A .csv file has a
+// "channel,time" row per spike and may start with a header. Any other
+// file has a line of space or comma separated times per channel, the
+// first line being channel 0. Lines starting with '#' are skipped.
+func LoadRecording(fileName string) (map[int][]float64, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	isCSV := strings.HasSuffix(strings.ToLower(fileName), ".csv")
+
+	channels := map[int][]float64{}
+
+	scanner := bufio.NewScanner(file)
+	line := 0
+	channel := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.FieldsFunc(text, func(c rune) bool { return c == ',' || c == ' ' || c == '\t' })
+
+		if isCSV {
+			if len(fields) == 0 {
+				continue
+			}
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("%s:%d: expected \"channel,time\"", fileName, line)
+			}
+			ch, err1 := strconv.Atoi(fields[0])
+			t, err2 := strconv.ParseFloat(fields[1], 64)
+			if err1 != nil || err2 != nil {
+				if line == 1 {
+					// Header
+					continue
+				}
+				return nil, fmt.Errorf("%s:%d: expected \"channel,time\"", fileName, line)
+			}
+			channels[ch] = append(channels[ch], t)
+		} else {
+			times := []float64{}
+			for _, f := range fields {
+				t, err := strconv.ParseFloat(f, 64)
+				if err != nil {
+					return nil, fmt.Errorf("%s:%d: %v", fileName, line, err)
+				}
+				times = append(times, t)
+			}
+			channels[channel] = times
+			channel++
+		}
+	}
+
+	for _, times := range channels {
+		sort.Float64s(times)
+	}
+
+	return channels, nil
+}
+
+// ChannelLane routes a recorded channel to a synapse.
+type ChannelLane struct {
+	Channel int
+	Synapse int
+}
+
+// PlaybackSpec describes a recording in the stimulus json, either in
+// place of noise (the settings' Noise section) or as a pattern (the
+// patterns file). For example:
+//
+//	{"File": "rec_1.csv", "Loop": true, "Scale": 2.0, "Offset": 10,
+//	 "Lanes": [{"Channel": 4, "Synapse": 0}, {"Channel": 7, "Synapse": 1}]}
+//
+// Without Lanes channel c feeds synapse c. Scale defaults to 1 and Period,
+// the recording's length, to just past its last spike.
+type PlaybackSpec struct {
+	File   string
+	Loop   bool
+	Period float64
+	Scale  float64
+	Offset float64
+	Lanes  []*ChannelLane
+}
+
+// Build loads the recording and creates a stream per lane, keyed by
+// synapse. dir is where File is found. Synapses outside [0, synCnt) are
+// ignored, they'd never be stepped.
+func (ps *PlaybackSpec) Build(dir string, dt float64, synCnt int) (map[int]*PlaybackStream, error) {
+	channels, err := LoadRecording(dir + ps.File)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := ps.Scale
+	if scale == 0 {
+		scale = 1.0
+	}
+	if scale < 0 {
+		return nil, errors.New("playback: Scale can't be negative")
+	}
+
+	lanes := ps.Lanes
+	if len(lanes) == 0 {
+		for ch := range channels {
+			lanes = append(lanes, &ChannelLane{Channel: ch, Synapse: ch})
+		}
+	}
+
+	// A common period keeps the channels in step when looping.
+	period := ps.Period
+	if period <= 0 {
+		for _, times := range channels {
+			if n := len(times); n > 0 && times[n-1]+dt > period {
+				period = times[n-1] + dt
+			}
+		}
+	}
+
+	streams := map[int]*PlaybackStream{}
+	for _, l := range lanes {
+		times, ok := channels[l.Channel]
+		if !ok {
+			return nil, fmt.Errorf("playback: %s has no channel (%d)", ps.File, l.Channel)
+		}
+		if ps.Loop && len(times) > 0 && times[len(times)-1] >= period {
+			return nil, fmt.Errorf("playback: channel (%d) has spikes past the Period", l.Channel)
+		}
+		if l.Synapse < 0 || l.Synapse >= synCnt {
+			fmt.Printf("Playback lane for synapse (%d) ignored, there are only %d synapses\n", l.Synapse, synCnt)
+			continue
+		}
+
+		s := NewPlaybackStream(times, period, dt)
+		s.SetId(l.Synapse)
+		s.SetScale(scale)
+		s.SetOffset(ps.Offset)
+		s.SetLoop(ps.Loop)
+		streams[l.Synapse] = s
+	}
+
+	return streams, nil
+}
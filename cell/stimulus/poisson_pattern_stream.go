@@ -1,12 +1,8 @@
 package stimulus
 
 import (
-	"fmt"
 	"math"
-	"math/rand"
-	"strings"
 
-	sll "github.com/emirpasic/gods/lists/singlylinkedlist"
 	"github.com/wdevore/Deuron5/deuron"
 )
 
@@ -14,44 +10,37 @@ import (
 // When the pattern has completed emission a new ISI is generated
 // as a delay.
 type PoissonPatternStream struct {
-	output byte
-
-	ran  *rand.Rand
-	seed int64
+	basePresenter
 
 	// Poisson properties
 	max    float64
 	spread float64
 	min    float64
 
-	autoReset bool
-	// When a pattern completes generate a new ISI automatically.
-	autoGenerateISI bool
-
-	// A collection of streams
-	patterns *sll.List
-	patItr   sll.Iterator
 	// isi = spike intervals
 	isi int // in milliseconds
 
 	delayCnt int
+
+	// A fixed ISI in milliseconds, otherwise 0 to use the model's Hertz.
+	period int
+
+	ctx *deuron.Context
 }
 
-func NewPoissonPatternStream(seed int64) *PoissonPatternStream {
+func NewPoissonPatternStream(seed int64, ctx *deuron.Context) *PoissonPatternStream {
 	s := new(PoissonPatternStream)
-	s.autoReset = true
-	s.seed = seed
-	s.ran = RanGen(seed)
+	s.baseInitialize(seed)
+	s.ctx = ctx
 
 	// Query model for initial values.
-	s.max = deuron.SimModel.GetFloat("Poisson_Pattern_max")
-	s.spread = deuron.SimModel.GetFloat("Poisson_Pattern_spread")
-	s.min = deuron.SimModel.GetFloat("Poisson_Pattern_min")
+	s.max = ctx.Model.GetFloat("Poisson_Pattern_max")
+	s.spread = ctx.Model.GetFloat("Poisson_Pattern_spread")
+	s.min = ctx.Model.GetFloat("Poisson_Pattern_min")
 
 	// s.isi = Generate(s.ran.Float64(), s.max, s.spread, s.min)
 	s.isi = s.genPoisson(s.max)
 
-	s.patterns = sll.New()
 	return s
 }
 
@@ -68,49 +57,40 @@ func (nps *PoissonPatternStream) poissonSmall(lambda float64) int {
 	k := 0
 	for p > L {
 		k++
-		p *= nps.ran.Float64()
+		p *= nps.ran.Uniform()
 	}
 
 	return k - 1
 }
 
+// SetPeriod fixes the ISI between presentations. 0 reverts to the
+// model's Hertz or a poisson ISI.
+func (nps *PoissonPatternStream) SetPeriod(period int) {
+	nps.period = period
+}
+
 // SetISI sets the "ISI" between pattern applications. The pattern
 // period itself could be longer than the interval.
 func (nps *PoissonPatternStream) SetISI(isi int) {
 	nps.isi = isi
 }
 
-func (nps *PoissonPatternStream) Add(strm IPatternStream) {
-	nps.patterns.Add(strm)
-}
-
-func (nps *PoissonPatternStream) IsComplete() bool {
-	return false
-}
-
-func (nps *PoissonPatternStream) EnableAutoReset() {
-	nps.autoReset = true
-}
-
 func (nps *PoissonPatternStream) Reset() {
 	// fmt.Println("--------------- POI pattern RESETing")
 	nps.ran.Seed(nps.seed)
-	deuron.SetPoissonSeed(1963)
 	nps.patternReset()
 }
 
-func (nps *PoissonPatternStream) Clear() {
-	nps.patterns.Clear()
-}
-
 func (nps *PoissonPatternStream) patternReset() {
 	nps.delayCnt = 0
 
-	nps.isi = int(deuron.SimModel.GetFloat("Hertz"))
-	if nps.isi == 0.0 {
-		nps.max = deuron.SimModel.GetFloat("Poisson_Pattern_max")
-		nps.spread = deuron.SimModel.GetFloat("Poisson_Pattern_spread")
-		nps.min = deuron.SimModel.GetFloat("Poisson_Pattern_min")
+	nps.isi = int(nps.ctx.Model.GetFloat("Hertz"))
+	if nps.period > 0 {
+		nps.isi = nps.period
+	} else if nps.isi == 0.0 {
+		nps.max = nps.ctx.Model.GetFloat("Poisson_Pattern_max")
+		nps.spread = nps.ctx.Model.GetFloat("Poisson_Pattern_spread")
+		nps.min = nps.ctx.Model.GetFloat("Poisson_Pattern_min")
 
 		// nps.isi = Generate(nps.ran.Float64(), nps.max, nps.spread, nps.min)
 		nps.isi = nps.poissonSmall(nps.max)
@@ -120,80 +100,57 @@ func (nps *PoissonPatternStream) patternReset() {
 	}
 
 	// fmt.Printf("isi: %d\n", nps.isi)
-	it := nps.patterns.Iterator()
-	for it.Next() {
-		stim := it.Value().(IPatternStream)
-		stim.Reset()
-	}
+	nps.resetStreams()
 }
 
 func (nps *PoissonPatternStream) Step() {
 	// Step all the streams when the ISI had ended.
 	// Once the pattern has completed we switch back to ISI.
 	if nps.delayCnt > nps.isi {
-		var complete bool
-		it := nps.patterns.Iterator()
-		for it.Next() {
-			stim := it.Value().(IPatternStream)
-			complete = complete || stim.Step()
-		}
-
-		if complete {
+		if nps.stepStreams() {
 			nps.patternReset()
 		}
 	} else {
 		nps.delayCnt++
-	}
-}
-
-func (nps *PoissonPatternStream) Begin() bool {
-	if nps.patterns.Empty() {
-		return false
-	}
-
-	nps.patItr = nps.patterns.Iterator()
-	nps.patItr.Begin()
-	return nps.patItr.Next()
-}
 
-func (nps *PoissonPatternStream) Next() bool {
-	if nps.patterns.Empty() {
-		return false
+		// The ISI just ended, decide if this presentation happens.
+		if nps.delayCnt > nps.isi && nps.skip() {
+			nps.patternReset()
+		}
 	}
-
-	return nps.patItr.Next()
 }
 
-// Stream returns the current pattern available
-func (nps *PoissonPatternStream) Stream() IPatternStream {
-	if nps.patterns.Empty() {
-		return nil
-	}
-	it := nps.patItr
-	stream := it.Value().(IPatternStream)
-	return stream
+// IsPresenting indicates if the pattern, rather than the ISI delay, is
+// currently being stepped.
+func (nps *PoissonPatternStream) IsPresenting() bool {
+	return nps.delayCnt > nps.isi
 }
 
-func (nps *PoissonPatternStream) ExpandStreams(scaler float64) {
-	if nps.patterns.Empty() {
-		return
+// State captures the ISI delay, RNG and each pattern's position.
+func (nps *PoissonPatternStream) State() interface{} {
+	m := map[string]interface{}{
+		"max":      nps.max,
+		"spread":   nps.spread,
+		"min":      nps.min,
+		"isi":      nps.isi,
+		"delayCnt": nps.delayCnt,
+		"Random":   nps.ran.State(),
+		"Patterns": nps.streamsState(),
 	}
 
-	it := nps.patterns.Iterator()
-	for it.Next() {
-		stim := it.Value().(*SpikeStream)
-		stim.Expand(int(scaler))
-	}
+	return m
 }
 
-func (nps PoissonPatternStream) String() string {
-	var s strings.Builder
+func (nps *PoissonPatternStream) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
 
-	it := nps.patterns.Iterator()
-	for it.Next() {
-		stim := it.Value().(*SpikeStream)
-		s.WriteString(fmt.Sprintf("%s\n", stim.String()))
-	}
+	nps.max = jmap["max"].(float64)
+	nps.spread = jmap["spread"].(float64)
+	nps.min = jmap["min"].(float64)
+	nps.isi = int(jmap["isi"].(float64))
+	nps.delayCnt = int(jmap["delayCnt"].(float64))
+
+	nps.ran.RestoreState(jmap["Random"])
 
-	return s.String()
+	nps.restoreStreams(jmap["Patterns"])
 }
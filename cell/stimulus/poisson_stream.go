@@ -16,7 +16,7 @@ func RanGen(seed int64) *rand.Rand {
 type PoissonStream struct {
 	basePatternStream
 
-	ran *rand.Rand
+	ran *deuron.Random
 
 	// Random seed
 	seed int64
@@ -27,19 +27,22 @@ type PoissonStream struct {
 
 	// Poisson properties
 	firingRate float64
+
+	ctx *deuron.Context
 }
 
 // NewPoissonStream creates a stream
-func NewPoissonStream(seed int64) IPatternStream {
+func NewPoissonStream(seed int64, ctx *deuron.Context) IPatternStream {
 	s := new(PoissonStream)
 	s.baseInitialize()
 
 	s.seed = seed
-	s.ran = RanGen(seed)
+	s.ran = deuron.NewRandom(seed)
+	s.ctx = ctx
 
-	s.firingRate = deuron.SimModel.GetFloat("Firing_Rate")
+	s.firingRate = ctx.Model.GetFloat("Firing_Rate")
 
-	s.isi = deuron.GenPoisson(s.firingRate)
+	s.isi = s.ran.GenPoisson(s.firingRate)
 
 	return s
 }
@@ -49,6 +52,12 @@ func (ss *PoissonStream) Initialize(firingRate float64) {
 	ss.Reset()
 }
 
+// SetFiringRate changes the rate from the next ISI onwards without
+// reseeding.
+func (ss *PoissonStream) SetFiringRate(firingRate float64) {
+	ss.firingRate = firingRate
+}
+
 // Attach connections to this stream
 // The given IConnection will have spikes routed into it.
 func (ss *PoissonStream) Attach(con cell.IConnection) {
@@ -114,12 +123,10 @@ func (ss *PoissonStream) EnableAutoReset() {
 
 // Reset generates a new ISI
 func (ss *PoissonStream) Reset() {
-	deuron.SeedPoisson(ss.seed)
-
 	ss.ran.Seed(ss.seed)
-	ss.firingRate = deuron.SimModel.GetFloat("Firing_Rate")
+	ss.firingRate = ss.ctx.Model.GetFloat("Firing_Rate")
 
-	ss.isi = deuron.GenPoisson(ss.firingRate)
+	ss.isi = ss.ran.GenPoisson(ss.firingRate)
 
 	// ss.isi = ss.generate(ss.max, ss.spread, ss.min)
 }
@@ -131,7 +138,7 @@ func (ss *PoissonStream) Step() bool {
 		// Time to generate a spike
 		ss.value = 1
 		// ss.isi = ss.generate(ss.max, ss.spread, ss.min)
-		ss.isi = deuron.GenPoisson(ss.firingRate)
+		ss.isi = ss.ran.GenPoisson(ss.firingRate)
 	} else {
 		ss.value = 0
 		ss.isi--
@@ -163,3 +170,24 @@ func (ss *PoissonStream) Input(v int) {
 func (ss *PoissonStream) Output() int {
 	return ss.value
 }
+
+func (ss *PoissonStream) State() interface{} {
+	m := map[string]interface{}{
+		"isi":        ss.isi,
+		"firingRate": ss.firingRate,
+		"value":      ss.value,
+		"Random":     ss.ran.State(),
+	}
+
+	return m
+}
+
+func (ss *PoissonStream) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
+
+	ss.isi = int(jmap["isi"].(float64))
+	ss.firingRate = jmap["firingRate"].(float64)
+	ss.value = int(jmap["value"].(float64))
+
+	ss.ran.RestoreState(jmap["Random"])
+}
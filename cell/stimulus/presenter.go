@@ -0,0 +1,200 @@
+package stimulus
+
+import (
+	"fmt"
+	"strings"
+
+	sll "github.com/emirpasic/gods/lists/singlylinkedlist"
+	"github.com/wdevore/Deuron5/deuron"
+)
+
+// IPatternPresenter presents a set of streams, a pattern, from time to
+// time. Presenters differ in when: at a fixed frequency (NPatternStream),
+// after poisson ISIs (PoissonPatternStream) or at scheduled times
+// (ScheduledPatternStream).
+type IPatternPresenter interface {
+	Name() string
+
+	Add(strm IPatternStream)
+
+	Reset()
+
+	// Step moves the presenter and, while presenting, its streams on by a
+	// step.
+	Step()
+
+	// IsPresenting indicates if the pattern, rather than the delay
+	// between presentations, is being stepped.
+	IsPresenting() bool
+
+	// Iteration of the streams.
+	Begin() bool
+	Next() bool
+	Stream() IPatternStream
+
+	// ExpandStreams inserts scaler, which needn't be whole, empty steps
+	// after every step of the spike streams.
+	ExpandStreams(scaler float64)
+
+	// Random is the generator stream transforms draw from unless they
+	// have their own.
+	Random() *deuron.Random
+
+	// SetProbability sets the chance that a presentation happens when
+	// it is due. Skipped presentations are just a longer delay.
+	SetProbability(p float64)
+
+	State() interface{}
+	RestoreState(state interface{})
+}
+
+// basePresenter is what every presenter has: the streams, a generator
+// and a name.
+type basePresenter struct {
+	name string
+
+	ran  *deuron.Random
+	seed int64
+
+	// Chance that a presentation happens when it is due.
+	probability float64
+
+	// A collection of streams
+	patterns *sll.List
+	patItr   sll.Iterator
+}
+
+func (bp *basePresenter) baseInitialize(seed int64) {
+	bp.seed = seed
+	bp.ran = deuron.NewRandom(seed)
+	bp.probability = 1.0
+	bp.patterns = sll.New()
+}
+
+func (bp *basePresenter) SetName(name string) {
+	bp.name = name
+}
+
+func (bp *basePresenter) Name() string {
+	return bp.name
+}
+
+func (bp *basePresenter) Random() *deuron.Random {
+	return bp.ran
+}
+
+func (bp *basePresenter) SetProbability(p float64) {
+	bp.probability = p
+}
+
+// skip decides if a presentation that is due is skipped.
+func (bp *basePresenter) skip() bool {
+	return bp.probability < 1.0 && bp.ran.Uniform() >= bp.probability
+}
+
+func (bp *basePresenter) Add(strm IPatternStream) {
+	bp.patterns.Add(strm)
+}
+
+func (bp *basePresenter) Clear() {
+	bp.patterns.Clear()
+}
+
+func (bp *basePresenter) resetStreams() {
+	it := bp.patterns.Iterator()
+	for it.Next() {
+		stim := it.Value().(IPatternStream)
+		stim.Reset()
+	}
+}
+
+// stepStreams steps every stream, even once one has completed, and
+// returns true if any completed.
+func (bp *basePresenter) stepStreams() bool {
+	var complete bool
+	it := bp.patterns.Iterator()
+	for it.Next() {
+		stim := it.Value().(IPatternStream)
+		if stim.Step() {
+			complete = true
+		}
+	}
+	return complete
+}
+
+func (bp *basePresenter) streamsState() []interface{} {
+	a := make([]interface{}, bp.patterns.Size())
+
+	it := bp.patterns.Iterator()
+	ind := 0
+	for it.Next() {
+		stim := it.Value().(IPatternStream)
+		a[ind] = stim.State()
+		ind++
+	}
+
+	return a
+}
+
+func (bp *basePresenter) restoreStreams(state interface{}) {
+	patterns := state.([]interface{})
+
+	it := bp.patterns.Iterator()
+	i := 0
+	for it.Next() {
+		stim := it.Value().(IPatternStream)
+		stim.RestoreState(patterns[i])
+		i++
+	}
+}
+
+func (bp *basePresenter) Begin() bool {
+	if bp.patterns.Empty() {
+		return false
+	}
+
+	bp.patItr = bp.patterns.Iterator()
+	bp.patItr.Begin()
+	return bp.patItr.Next()
+}
+
+func (bp *basePresenter) Next() bool {
+	if bp.patterns.Empty() {
+		return false
+	}
+
+	return bp.patItr.Next()
+}
+
+// Stream returns the current pattern available
+func (bp *basePresenter) Stream() IPatternStream {
+	if bp.patterns.Empty() {
+		return nil
+	}
+	it := bp.patItr
+	stream := it.Value().(IPatternStream)
+	return stream
+}
+
+func (bp *basePresenter) ExpandStreams(scaler float64) {
+	// Only spike streams are expanded, recordings have their own scale.
+	it := bp.patterns.Iterator()
+	for it.Next() {
+		if stim, ok := it.Value().(*SpikeStream); ok {
+			stim.Stretch(scaler + 1.0)
+		}
+	}
+}
+
+func (bp basePresenter) String() string {
+	var s strings.Builder
+
+	it := bp.patterns.Iterator()
+	for it.Next() {
+		if stim, ok := it.Value().(*SpikeStream); ok {
+			s.WriteString(fmt.Sprintf("%s\n", stim.String()))
+		}
+	}
+
+	return s.String()
+}
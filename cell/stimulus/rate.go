@@ -0,0 +1,293 @@
+package stimulus
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IRate is a firing rate that changes with time. Rates are in spikes
+// per millisecond, the same as the model's Firing_Rate, and t is in
+// milliseconds since the stream was reset.
+type IRate interface {
+	Rate(t float64) float64
+	// Max is an upper bound of Rate used for thinning.
+	Max() float64
+}
+
+// ConstantRate never changes.
+type ConstantRate struct {
+	Value float64
+}
+
+func (r *ConstantRate) Rate(t float64) float64 {
+	return r.Value
+}
+
+func (r *ConstantRate) Max() float64 {
+	return r.Value
+}
+
+// SineRate oscillates around Base:
+// Base + Amplitude * sin(2*pi*t/Period + Phase), clamped at 0.
+type SineRate struct {
+	Base      float64
+	Amplitude float64
+	Period    float64
+	Phase     float64
+}
+
+func (r *SineRate) Rate(t float64) float64 {
+	return math.Max(0.0, r.Base+r.Amplitude*math.Sin(2.0*math.Pi*t/r.Period+r.Phase))
+}
+
+func (r *SineRate) Max() float64 {
+	return r.Base + math.Abs(r.Amplitude)
+}
+
+// StepRate holds Rates[i] from Times[i] until Times[i+1]. Before
+// Times[0] the rate is Rates[0].
+type StepRate struct {
+	Times []float64
+	Rates []float64
+}
+
+func (r *StepRate) Rate(t float64) float64 {
+	// Index of the first time after t
+	i := sort.SearchFloat64s(r.Times, math.Nextafter(t, math.Inf(1)))
+	if i == 0 {
+		return r.Rates[0]
+	}
+	return r.Rates[i-1]
+}
+
+func (r *StepRate) Max() float64 {
+	return maxOf(r.Rates)
+}
+
+// CurveRate linearly interpolates between points and holds the end
+// values outside of them.
+type CurveRate struct {
+	Times []float64
+	Rates []float64
+}
+
+func (r *CurveRate) Rate(t float64) float64 {
+	n := len(r.Times)
+	if t <= r.Times[0] {
+		return r.Rates[0]
+	}
+	if t >= r.Times[n-1] {
+		return r.Rates[n-1]
+	}
+
+	i := sort.SearchFloat64s(r.Times, t)
+	t0, t1 := r.Times[i-1], r.Times[i]
+	r0, r1 := r.Rates[i-1], r.Rates[i]
+
+	return r0 + (r1-r0)*(t-t0)/(t1-t0)
+}
+
+func (r *CurveRate) Max() float64 {
+	return maxOf(r.Rates)
+}
+
+func maxOf(values []float64) float64 {
+	m := 0.0
+	for _, v := range values {
+		m = math.Max(m, v)
+	}
+	return m
+}
+
+// LoadRateCurve reads a curve where each line is "time rate", space or
+// comma separated. Blank lines and lines starting with '#' are skipped.
+func LoadRateCurve(fileName string) (*CurveRate, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	curve := new(CurveRate)
+
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.FieldsFunc(text, func(c rune) bool { return c == ',' || c == ' ' || c == '\t' })
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected \"time rate\"", fileName, line)
+		}
+
+		t, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", fileName, line, err)
+		}
+		r, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", fileName, line, err)
+		}
+
+		if n := len(curve.Times); n > 0 && t <= curve.Times[n-1] {
+			return nil, fmt.Errorf("%s:%d: times must increase", fileName, line)
+		}
+
+		curve.Times = append(curve.Times, t)
+		curve.Rates = append(curve.Rates, r)
+	}
+
+	if len(curve.Times) == 0 {
+		return nil, fmt.Errorf("%s: no points", fileName)
+	}
+
+	return curve, nil
+}
+
+// RateSpec describes the noise of a synapse in the stimulus settings.
+// Type is one of the rates "constant", "sine", "step" or "file", which
+// give an inhomogeneous poisson stream, or "deadtime" or "gamma", which
+// give a renewal stream.
+type RateSpec struct {
+	Type string
+
+	// constant, deadtime and gamma: Rate. sine: Rate is the base.
+	Rate      float64
+	Amplitude float64
+	Period    float64
+	Phase     float64
+
+	// step
+	Times []float64
+	Rates []float64
+
+	// file, relative to the stimulus directory.
+	File string
+
+	// deadtime, in ms.
+	DeadTime float64
+	// gamma
+	Order int
+}
+
+// NewStream creates the noise stream. dt is the step size in ms.
+func (rs *RateSpec) NewStream(seed int64, dir string, dt float64) (IPatternStream, error) {
+	switch rs.Type {
+	case "deadtime":
+		if rs.Rate <= 0 || rs.DeadTime < 0 || rs.DeadTime >= 1.0/rs.Rate {
+			return nil, errors.New("deadtime: Rate must be > 0 and DeadTime within [0, 1/Rate)")
+		}
+		return NewRenewalStream(seed, &DeadTimeInterval{Rate: rs.Rate, DeadTime: rs.DeadTime}, dt), nil
+	case "gamma":
+		if rs.Rate <= 0 || rs.Order < 1 {
+			return nil, errors.New("gamma: Rate must be > 0 and Order >= 1")
+		}
+		return NewRenewalStream(seed, &GammaInterval{Rate: rs.Rate, Order: rs.Order}, dt), nil
+	}
+
+	rate, err := rs.Build(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewInhomogeneousPoissonStream(seed, rate, dt), nil
+}
+
+// Build creates the rate. dir is where File is found.
+func (rs *RateSpec) Build(dir string) (IRate, error) {
+	switch rs.Type {
+	case "constant", "":
+		return &ConstantRate{Value: rs.Rate}, nil
+	case "sine":
+		if rs.Period <= 0 {
+			return nil, errors.New("sine rate: Period must be > 0")
+		}
+		return &SineRate{Base: rs.Rate, Amplitude: rs.Amplitude, Period: rs.Period, Phase: rs.Phase}, nil
+	case "step":
+		if len(rs.Times) == 0 || len(rs.Times) != len(rs.Rates) {
+			return nil, errors.New("step rate: Times and Rates must be the same, non zero, length")
+		}
+		if !sort.Float64sAreSorted(rs.Times) {
+			return nil, errors.New("step rate: Times must increase")
+		}
+		return &StepRate{Times: rs.Times, Rates: rs.Rates}, nil
+	case "file":
+		return LoadRateCurve(dir + rs.File)
+	}
+
+	return nil, fmt.Errorf("unknown rate type (%s)", rs.Type)
+}
+
+// LaneRateSpec is the rate of the noise feeding one synapse.
+type LaneRateSpec struct {
+	Synapse int
+	RateSpec
+}
+
+// NoiseSpec is the optional "Noise" section of the stimulus settings,
+// for example:
+//
+//	"Noise": {
+//		"Default": {"Type": "sine", "Rate": 0.005, "Amplitude": 0.003, "Period": 1000},
+//		"Lanes": [
+//			{"Synapse": 3, "Type": "step", "Times": [0, 5000], "Rates": [0.005, 0.02]}
+//		],
+//		"Groups": [
+//			{"Synapses": [0, 1, 2, 3], "Rate": 0.01, "Correlation": 0.2, "Jitter": 1}
+//		],
+//		"Playback": [
+//			{"File": "rec_1.csv", "Loop": true, "Lanes": [{"Channel": 2, "Synapse": 5}]}
+//		]
+//	}
+//
+// Synapses fed by a recording, and then those in a correlated group, get
+// that before anything else. Synapses without a rate get the usual fixed
+// Firing_Rate noise.
+type NoiseSpec struct {
+	Default  *RateSpec
+	Lanes    []*LaneRateSpec
+	Groups   []*CorrelationSpec
+	Playback []*PlaybackSpec
+}
+
+// ParseNoiseSpec converts the generic json of the settings file.
+func ParseNoiseSpec(v interface{}) (*NoiseSpec, error) {
+	ns := new(NoiseSpec)
+	if v == nil {
+		return ns, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns, nil
+}
+
+// RateFor returns the rate spec for a synapse or nil if it has none.
+func (ns *NoiseSpec) RateFor(synapse int) *RateSpec {
+	for _, l := range ns.Lanes {
+		if l.Synapse == synapse {
+			return &l.RateSpec
+		}
+	}
+	return ns.Default
+}
@@ -0,0 +1,184 @@
+package stimulus
+
+import (
+	"math"
+
+	"github.com/wdevore/Deuron5/cell"
+	"github.com/wdevore/Deuron5/deuron"
+)
+
+// IInterval draws the interspike intervals (ms) of a renewal process.
+type IInterval interface {
+	Next(ran *deuron.Random) float64
+	// Mean ISI in ms
+	Mean() float64
+	// CV is the theoretical coefficient of variation of the ISIs.
+	CV() float64
+}
+
+// DeadTimeInterval is poisson with a refractory dead time: ISI = DeadTime
+// + exponential, where the exponential's rate is chosen so that the mean
+// rate is still Rate.
+type DeadTimeInterval struct {
+	Rate     float64 // spikes/ms
+	DeadTime float64 // ms
+}
+
+func (d *DeadTimeInterval) Next(ran *deuron.Random) float64 {
+	return d.DeadTime + (d.Mean()-d.DeadTime)*exponential(ran)
+}
+
+func (d *DeadTimeInterval) Mean() float64 {
+	return 1.0 / d.Rate
+}
+
+func (d *DeadTimeInterval) CV() float64 {
+	return (d.Mean() - d.DeadTime) / d.Mean()
+}
+
+// GammaInterval has ISIs drawn from a Gamma distribution of integer
+// Order k with mean 1/Rate. k = 1 is poisson and larger k are more
+// regular.
+type GammaInterval struct {
+	Rate  float64 // spikes/ms
+	Order int
+}
+
+func (g *GammaInterval) Next(ran *deuron.Random) float64 {
+	// A sum of k exponentials each with mean 1/(k*Rate)
+	sum := 0.0
+	for i := 0; i < g.Order; i++ {
+		sum += exponential(ran)
+	}
+	return sum * g.Mean() / float64(g.Order)
+}
+
+func (g *GammaInterval) Mean() float64 {
+	return 1.0 / g.Rate
+}
+
+func (g *GammaInterval) CV() float64 {
+	return 1.0 / math.Sqrt(float64(g.Order))
+}
+
+// exponential with a mean of 1.
+func exponential(ran *deuron.Random) float64 {
+	return -math.Log(1.0 - ran.Uniform())
+}
+
+// RenewalStream is noise whose ISIs are independent draws from an
+// IInterval. Unlike PoissonStream, which counts whole steps and so
+// truncates every ISI and adds a step to it, spike times are kept in
+// continuous time and a step outputs a spike if a spike time falls
+// inside it. The statistics are then exact at any time step as long as
+// the step is shorter than the shortest ISI, otherwise spikes in the
+// same step merge.
+type RenewalStream struct {
+	basePatternStream
+
+	ran *deuron.Random
+
+	// Random seed
+	seed int64
+
+	interval IInterval
+
+	// Step size in ms.
+	dt float64
+
+	// Steps since reset
+	step int
+
+	// Time of the next spike in ms.
+	nextT float64
+}
+
+// NewRenewalStream creates a stream that steps dt milliseconds at a time.
+func NewRenewalStream(seed int64, interval IInterval, dt float64) IPatternStream {
+	s := new(RenewalStream)
+	s.baseInitialize()
+
+	s.seed = seed
+	s.ran = deuron.NewRandom(seed)
+	s.interval = interval
+	s.dt = dt
+
+	s.Reset()
+
+	return s
+}
+
+func (ss *RenewalStream) Interval() IInterval {
+	return ss.interval
+}
+
+// ----------------------------------------------
+// IPatternStream methods
+// ----------------------------------------------
+func (ss *RenewalStream) EnableAutoReset() {
+	// Not applicable
+}
+
+func (ss *RenewalStream) Reset() {
+	ss.ran.Seed(ss.seed)
+	ss.step = 0
+	ss.value = 0
+	ss.nextT = ss.interval.Next(ss.ran)
+}
+
+func (ss *RenewalStream) Step() bool {
+	ss.value = 0
+
+	ss.step++
+	end := float64(ss.step) * ss.dt
+
+	for ss.nextT < end {
+		ss.value = 1
+		ss.nextT += ss.interval.Next(ss.ran)
+	}
+
+	it := ss.cons.Iterator()
+	for it.Next() {
+		conn := it.Value().(cell.IConnection)
+		conn.Input(ss.value)
+	}
+
+	return false
+}
+
+func (ss *RenewalStream) IsComplete() bool {
+	return false // This type of stream never completes
+}
+
+// ----------------------------------------------
+// IBitStream methods
+// ----------------------------------------------
+
+func (ss *RenewalStream) Input(v int) {
+	// Not applicable.
+}
+
+func (ss *RenewalStream) Output() int {
+	return ss.value
+}
+
+func (ss *RenewalStream) State() interface{} {
+	m := map[string]interface{}{
+		"step":   ss.step,
+		"nextT":  ss.nextT,
+		"value":  ss.value,
+		"Random": ss.ran.State(),
+	}
+
+	return m
+}
+
+func (ss *RenewalStream) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
+
+	ss.step = int(jmap["step"].(float64))
+	ss.nextT = jmap["nextT"].(float64)
+	ss.value = int(jmap["value"].(float64))
+
+	ss.ran.RestoreState(jmap["Random"])
+}
@@ -0,0 +1,104 @@
+package stimulus
+
+import (
+	"errors"
+	"sort"
+)
+
+// ScheduledPatternStream presents its streams at listed onsets (ms). If
+// repeat is > 0 the schedule starts again every repeat ms. An onset that
+// arrives while the pattern is still being presented waits for it to
+// complete.
+type ScheduledPatternStream struct {
+	basePresenter
+
+	onsets []int
+	repeat int
+
+	// Steps since reset
+	t int
+
+	// Next onset and how many times the schedule has repeated.
+	next  int
+	cycle int
+
+	presenting bool
+}
+
+func NewScheduledPatternStream(seed int64, onsets []int, repeat int) (*ScheduledPatternStream, error) {
+	sorted := make([]int, len(onsets))
+	copy(sorted, onsets)
+	sort.Ints(sorted)
+
+	if repeat > 0 && len(sorted) > 0 && sorted[len(sorted)-1] >= repeat {
+		return nil, errors.New("scheduled presenter: onsets must be before the repeat")
+	}
+
+	s := new(ScheduledPatternStream)
+	s.baseInitialize(seed)
+	s.onsets = sorted
+	s.repeat = repeat
+	return s, nil
+}
+
+func (sps *ScheduledPatternStream) Reset() {
+	sps.ran.Seed(sps.seed)
+	sps.t = 0
+	sps.next = 0
+	sps.cycle = 0
+	sps.presenting = false
+	sps.resetStreams()
+}
+
+func (sps *ScheduledPatternStream) onset() int {
+	return sps.onsets[sps.next] + sps.cycle*sps.repeat
+}
+
+func (sps *ScheduledPatternStream) Step() {
+	if !sps.presenting && sps.next < len(sps.onsets) && sps.t >= sps.onset() {
+		sps.presenting = !sps.skip()
+
+		sps.next++
+		if sps.next == len(sps.onsets) && sps.repeat > 0 {
+			sps.next = 0
+			sps.cycle++
+		}
+	}
+
+	if sps.presenting && sps.stepStreams() {
+		sps.presenting = false
+		sps.resetStreams()
+	}
+
+	sps.t++
+}
+
+func (sps *ScheduledPatternStream) IsPresenting() bool {
+	return sps.presenting
+}
+
+func (sps *ScheduledPatternStream) State() interface{} {
+	m := map[string]interface{}{
+		"t":          sps.t,
+		"next":       sps.next,
+		"cycle":      sps.cycle,
+		"presenting": sps.presenting,
+		"Random":     sps.ran.State(),
+		"Patterns":   sps.streamsState(),
+	}
+
+	return m
+}
+
+func (sps *ScheduledPatternStream) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
+
+	sps.t = int(jmap["t"].(float64))
+	sps.next = int(jmap["next"].(float64))
+	sps.cycle = int(jmap["cycle"].(float64))
+	sps.presenting = jmap["presenting"].(bool)
+
+	sps.ran.RestoreState(jmap["Random"])
+
+	sps.restoreStreams(jmap["Patterns"])
+}
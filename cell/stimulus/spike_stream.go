@@ -2,9 +2,11 @@ package stimulus
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/wdevore/Deuron5/cell"
+	"github.com/wdevore/Deuron5/deuron"
 )
 
 // SpikeStream provides a spiking stimulus stream
@@ -30,6 +32,15 @@ type SpikeStream struct {
 	// The pattern output and possibly expanded
 	expanded []int
 
+	// The stretched pattern before the pipeline is applied.
+	base []int
+
+	// How much the pattern was stretched to make base.
+	stretch float64
+
+	// Transforms applied to base each time the stream resets.
+	pipeline *Pipeline
+
 	idx int
 }
 
@@ -39,6 +50,7 @@ func NewSpikeStream() IPatternStream {
 	s.baseInitialize()
 
 	s.autoReset = false
+	s.stretch = 1.0
 
 	s.Reset()
 	s.EnableAutoReset()
@@ -66,6 +78,25 @@ func (ss *SpikeStream) Reset() {
 	// ss.idx = len(ss.expanded) - 1  // end to start
 	ss.idx = 0 // start to end
 	ss.value = 0
+
+	if ss.pipeline != nil && ss.base != nil {
+		ss.expanded = ss.pipeline.Apply(ss.base)
+	}
+}
+
+// SetJitter moves each spike by up to +/- jitter steps every time the
+// stream resets, using ran.
+func (ss *SpikeStream) SetJitter(jitter int, ran *deuron.Random) {
+	ss.SetPipeline(NewPipeline(ran, &UniformJitter{Width: jitter}))
+}
+
+// SetPipeline sets the transforms applied every time the stream resets.
+func (ss *SpikeStream) SetPipeline(pipeline *Pipeline) {
+	ss.pipeline = pipeline
+}
+
+func (ss *SpikeStream) Pipeline() *Pipeline {
+	return ss.pipeline
 }
 
 func (ss *SpikeStream) Step() bool {
@@ -133,6 +164,7 @@ func (ss *SpikeStream) SetSpikes(sp []int) {
 		ss.pattern[t] = spik
 		ss.expanded[t] = spik
 	}
+	ss.Stretch(1.0)
 	ss.Reset()
 }
 
@@ -161,27 +193,95 @@ func (ss *SpikeStream) SetSpikesFromString(sp string) {
 		}
 	}
 
+	ss.Stretch(1.0)
 	ss.Reset()
 }
 
+// Expand inserts scale empty steps after every step of the pattern.
 func (ss *SpikeStream) Expand(scale int) {
-	ss.expanded = []int{}
+	ss.Stretch(float64(scale + 1))
+}
 
-	for _, spik := range ss.pattern {
-		ss.expanded = append(ss.expanded, spik)
-		for i := 0; i < scale; i++ {
-			ss.expanded = append(ss.expanded, 0)
-		}
-	}
+// Stretch warps the pattern's time by factor, which needn't be whole.
+// For example, 1.5 turns 10 steps into 15.
+func (ss *SpikeStream) Stretch(factor float64) {
+	st := &Stretch{Factor: factor}
+	ss.stretch = factor
+	ss.base = st.Apply(ss.pattern, nil)
+
+	ss.expanded = make([]int, len(ss.base))
+	copy(ss.expanded, ss.base)
+}
+
+// Length is the steps of the stretched pattern.
+func (ss *SpikeStream) Length() int {
+	return len(ss.base)
 }
 
 func (ss *SpikeStream) Clear(t int) {
-	if t > len(ss.pattern) {
+	if t >= len(ss.pattern) {
 		fmt.Println("SpikeStream: bad clear position")
 		return
 	}
 	ss.pattern[t] = 0
-	ss.expanded[t] = 0 // This isn't quite correct "t" should be scaled
+
+	st := int(math.Round(float64(t) * ss.stretch))
+	if st < len(ss.expanded) {
+		ss.expanded[st] = 0
+	}
+	if st < len(ss.base) {
+		ss.base[st] = 0
+	}
+}
+
+func (ss *SpikeStream) State() interface{} {
+	m := map[string]interface{}{
+		"complete":  ss.complete,
+		"autoReset": ss.autoReset,
+		"idx":       ss.idx,
+		"value":     ss.value,
+		"expanded":  ss.expanded,
+		"base":      ss.base,
+		"stretch":   ss.stretch,
+	}
+
+	if ss.pipeline != nil {
+		m["Pipeline"] = ss.pipeline.State()
+	}
+
+	return m
+}
+
+func (ss *SpikeStream) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
+
+	ss.complete = jmap["complete"].(bool)
+	ss.autoReset = jmap["autoReset"].(bool)
+	ss.idx = int(jmap["idx"].(float64))
+	ss.value = int(jmap["value"].(float64))
+
+	// The expansion depends on the StimulusScaler at the time.
+	ss.expanded = toInts(jmap["expanded"])
+	ss.base = toInts(jmap["base"])
+	ss.stretch = jmap["stretch"].(float64)
+
+	if ss.pipeline != nil {
+		ss.pipeline.RestoreState(jmap["Pipeline"])
+	}
+}
+
+func toInts(json interface{}) []int {
+	if json == nil {
+		return nil
+	}
+
+	values := json.([]interface{})
+	ints := make([]int, len(values))
+	for i, v := range values {
+		ints[i] = int(v.(float64))
+	}
+
+	return ints
 }
 
 func (ss SpikeStream) ToString(reverse bool) string {
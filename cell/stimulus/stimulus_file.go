@@ -0,0 +1,324 @@
+package stimulus
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/wdevore/Deuron5/deuron"
+)
+
+// StimulusVersion is the version of the stimulus format this code reads.
+const StimulusVersion = 1
+
+// StimulusFile describes one or more patterns and which synapses their
+// lanes feed. For example, stimulus/<name>.patterns.json:
+//
+//	{
+//		"Version": 1,
+//		"Name": "stim_4",
+//		"Description": "Two patterns sharing synapse 2",
+//		"Patterns": [
+//			{
+//				"Name": "A",
+//				"Period": 50,
+//				"Probability": 0.8,
+//				"Jitter": 1,
+//				"Transforms": [
+//					{"Type": "gauss", "Sigma": 1.5},
+//					{"Type": "delete", "Probability": 0.1}
+//				],
+//				"Reseed": true,
+//				"Lanes": [
+//					{"Name": "a0", "Synapse": 0, "Spikes": "..|...|.."},
+//					{"Name": "a1", "Synapse": 2, "Spikes": "|....|..."}
+//				]
+//			},
+//			{
+//				"Name": "B",
+//				"Timing": "scheduled",
+//				"Schedule": [100, 400],
+//				"Repeat": 1000,
+//				"Lanes": [
+//					{"Synapse": 2, "Spikes": ".|.|.|..."}
+//				]
+//			}
+//		]
+//	}
+//
+// Spikes use the same '|' and '.' characters as the old .txt format.
+type StimulusFile struct {
+	Version     int
+	Name        string
+	Description string
+
+	Patterns []*PatternSpec
+}
+
+// PatternSpec is a set of lanes presented together.
+type PatternSpec struct {
+	Name string
+
+	// Timing is when the pattern is presented: "periodic", "poisson" or
+	// "scheduled". Missing means scheduled if there is a Schedule,
+	// otherwise poisson.
+	Timing string
+
+	// poisson: milliseconds of delay after each presentation. 0 means
+	// the delay is the model's Hertz as a period, or a poisson ISI if
+	// Hertz is 0 too.
+	// periodic: milliseconds from one presentation's onset to the next.
+	// 0 means use the model's Hertz.
+	Period int
+
+	// scheduled: onsets in milliseconds, repeated every Repeat
+	// milliseconds if Repeat isn't 0.
+	Schedule []int
+	Repeat   int
+
+	// Chance [0, 1] that the pattern is presented when it is due.
+	// Missing means 1.
+	Probability *float64
+
+	// Each spike is moved by up to +/- Jitter steps every presentation.
+	Jitter int
+
+	// Applied to each lane, after Jitter, every presentation.
+	Transforms []*TransformSpec
+	// Reseed gives each lane its own generator, reseeded every
+	// presentation, rather than drawing from the presenter's.
+	Reseed bool
+
+	Lanes []*LaneSpec
+
+	// Recorded lanes, played once per presentation. Loop is ignored.
+	Recording *PlaybackSpec
+}
+
+// LaneSpec is a single spike train and the synapse it feeds.
+type LaneSpec struct {
+	Name    string
+	Synapse int
+	Spikes  string
+}
+
+// LoadStimulusFile reads the versioned json format.
+func LoadStimulusFile(fileName string) (*StimulusFile, error) {
+	byteValue, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	sf := new(StimulusFile)
+	err = json.Unmarshal(byteValue, sf)
+	if err != nil {
+		return nil, err
+	}
+
+	if sf.Version != StimulusVersion {
+		return nil, fmt.Errorf("%s: unsupported stimulus version (%d)", fileName, sf.Version)
+	}
+
+	for _, p := range sf.Patterns {
+		if p.Probability == nil {
+			one := 1.0
+			p.Probability = &one
+		}
+	}
+
+	return sf, nil
+}
+
+// LoadLegacyStimulus reads the old .txt format where each line is a lane
+// feeding the synapse with the same index.
+func LoadLegacyStimulus(fileName string) (*StimulusFile, error) {
+	patternsFile, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer patternsFile.Close()
+
+	one := 1.0
+	pattern := &PatternSpec{Name: "1", Probability: &one}
+
+	scanner := bufio.NewScanner(patternsFile)
+	ind := 0
+	for scanner.Scan() {
+		pattern.Lanes = append(pattern.Lanes, &LaneSpec{
+			Name:    fmt.Sprintf("%d", ind),
+			Synapse: ind,
+			Spikes:  scanner.Text(),
+		})
+		ind++
+	}
+
+	sf := new(StimulusFile)
+	sf.Version = StimulusVersion
+	sf.Patterns = []*PatternSpec{pattern}
+
+	return sf, nil
+}
+
+// LoadStimulus looks for dir/<name>.patterns.json and falls back to the
+// old dir/<name>.txt.
+func LoadStimulus(dir, name string) (*StimulusFile, error) {
+	fileName := dir + name + ".patterns.json"
+	if _, err := os.Stat(fileName); err == nil {
+		fmt.Printf("Opened stimulus (%s)\n", fileName)
+		return LoadStimulusFile(fileName)
+	}
+
+	fileName = dir + name + ".txt"
+	sf, err := LoadLegacyStimulus(fileName)
+	if err == nil {
+		fmt.Printf("Opened stimulus (%s)\n", fileName)
+	}
+
+	return sf, err
+}
+
+// Build creates a presenter per pattern. A lane's stream id is the
+// synapse it feeds. dir is where recordings are found and scaler is the
+// StimulusScaler, the empty steps inserted after every step of a lane.
+func (sf *StimulusFile) Build(ctx *deuron.Context, dir string, scaler float64) ([]IPatternPresenter, error) {
+	presenters := []IPatternPresenter{}
+
+	// TimeStep is in microseconds.
+	dt := ctx.Model.GetFloat("TimeStep") / 1000.0
+
+	for _, p := range sf.Patterns {
+		presenter, err := p.newPresenter(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %s: %v", p.Name, err)
+		}
+		presenter.SetProbability(*p.Probability)
+
+		transforms := []ITransform{}
+		if p.Jitter > 0 {
+			transforms = append(transforms, &UniformJitter{Width: p.Jitter})
+		}
+		for _, ts := range p.Transforms {
+			t, err := ts.Build()
+			if err != nil {
+				return nil, fmt.Errorf("pattern %s: %v", p.Name, err)
+			}
+			transforms = append(transforms, t)
+		}
+
+		for i, lane := range p.Lanes {
+			spk := NewSpikeStream().(*SpikeStream)
+			spk.SetId(lane.Synapse)
+			spk.SetSpikesFromString(lane.Spikes)
+			spk.Stretch(scaler + 1.0)
+
+			if p.Reseed {
+				seed := ctx.DeriveSeed(fmt.Sprintf("pattern/%s/%d", p.Name, i))
+				spk.SetPipeline(NewSeededPipeline(seed, transforms...))
+			} else if len(transforms) > 0 {
+				spk.SetPipeline(NewPipeline(presenter.Random(), transforms...))
+			}
+
+			presenter.Add(spk)
+		}
+
+		if p.Recording != nil {
+			recording := *p.Recording
+			recording.Loop = false
+
+			streams, err := recording.Build(dir, dt, int(ctx.Model.GetFloat("Synapse_Count")))
+			if err != nil {
+				return nil, fmt.Errorf("pattern %s: %v", p.Name, err)
+			}
+
+			// In synapse order so that presenters are stepped the same
+			// way every run.
+			for _, syn := range sortedKeys(streams) {
+				presenter.Add(streams[syn])
+			}
+		}
+
+		// The next presentation would begin as soon as this one ends.
+		if nps, ok := presenter.(*NPatternStream); ok && presenterLength(nps) > nps.Period() {
+			fmt.Printf("Warning: pattern %s is longer than its period (%d ms), it's presented back to back.\n", p.Name, nps.Period())
+		}
+
+		presenters = append(presenters, presenter)
+	}
+
+	return presenters, nil
+}
+
+// newPresenter creates the presenter for the pattern's timing.
+func (p *PatternSpec) newPresenter(ctx *deuron.Context) (IPatternPresenter, error) {
+	seed := ctx.DeriveSeed("pattern/" + p.Name)
+	hertz := ctx.Model.GetFloat("Hertz")
+
+	timing := p.Timing
+	if timing == "" {
+		if len(p.Schedule) > 0 {
+			timing = "scheduled"
+		} else {
+			timing = "poisson"
+		}
+	}
+
+	switch timing {
+	case "periodic":
+		presenter := NewNPatternStream(seed)
+		presenter.SetName(p.Name)
+		if p.Period > 0 {
+			presenter.SetPeriod(p.Period)
+		} else if hertz > 0 {
+			presenter.SetHertz(hertz)
+		} else {
+			return nil, fmt.Errorf("periodic timing needs a Period or the model's Hertz")
+		}
+		return presenter, nil
+	case "poisson":
+		presenter := NewPoissonPatternStream(seed, ctx)
+		presenter.SetName(p.Name)
+		presenter.SetPeriod(p.Period)
+		return presenter, nil
+	case "scheduled":
+		presenter, err := NewScheduledPatternStream(seed, p.Schedule, p.Repeat)
+		if err != nil {
+			return nil, err
+		}
+		presenter.SetName(p.Name)
+		return presenter, nil
+	}
+
+	return nil, fmt.Errorf("unknown timing (%s)", timing)
+}
+
+// presenterLength is the steps of the presenter's longest stream.
+func presenterLength(presenter IPatternPresenter) int {
+	length := 0
+	for more := presenter.Begin(); more; more = presenter.Next() {
+		l := 0
+		switch stream := presenter.Stream().(type) {
+		case *SpikeStream:
+			l = stream.Length()
+		case *PlaybackStream:
+			l = int(math.Ceil(stream.Length()))
+		}
+		if l > length {
+			length = l
+		}
+	}
+	return length
+}
+
+func sortedKeys(streams map[int]*PlaybackStream) []int {
+	keys := []int{}
+	for k := range streams {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,209 @@
+package stimulus
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/wdevore/Deuron5/deuron"
+)
+
+// ITransform changes a spike train, for example, to add temporal noise
+// to a pattern each time it is presented. spikes isn't modified.
+type ITransform interface {
+	Apply(spikes []int, ran *deuron.Random) []int
+}
+
+// place sets a spike at t clamped to the train.
+func place(train []int, t int) {
+	if t < 0 {
+		t = 0
+	} else if t >= len(train) {
+		t = len(train) - 1
+	}
+	train[t] = 1
+}
+
+// Stretch warps time by Factor, > 1 stretches and < 1 compresses. A
+// spike at t moves to round(t*Factor). Compressed spikes may merge.
+type Stretch struct {
+	Factor float64
+}
+
+func (s *Stretch) Apply(spikes []int, ran *deuron.Random) []int {
+	out := make([]int, int(math.Round(float64(len(spikes))*s.Factor)))
+	if len(out) == 0 {
+		return out
+	}
+
+	for t, spik := range spikes {
+		if spik != 0 {
+			place(out, int(math.Round(float64(t)*s.Factor)))
+		}
+	}
+	return out
+}
+
+// UniformJitter moves each spike by a whole number of steps within
+// +/- Width.
+type UniformJitter struct {
+	Width int
+}
+
+func (j *UniformJitter) Apply(spikes []int, ran *deuron.Random) []int {
+	out := make([]int, len(spikes))
+	for t, spik := range spikes {
+		if spik != 0 {
+			place(out, t+int(ran.Uniform()*float64(2*j.Width+1))-j.Width)
+		}
+	}
+	return out
+}
+
+// GaussianJitter moves each spike by a normally distributed number of
+// steps with a standard deviation of Sigma.
+type GaussianJitter struct {
+	Sigma float64
+}
+
+func (j *GaussianJitter) Apply(spikes []int, ran *deuron.Random) []int {
+	out := make([]int, len(spikes))
+	for t, spik := range spikes {
+		if spik != 0 {
+			place(out, t+int(math.Round(ran.Normal()*j.Sigma)))
+		}
+	}
+	return out
+}
+
+// Delete removes each spike with Probability.
+type Delete struct {
+	Probability float64
+}
+
+func (d *Delete) Apply(spikes []int, ran *deuron.Random) []int {
+	out := make([]int, len(spikes))
+	for t, spik := range spikes {
+		if spik != 0 && ran.Uniform() >= d.Probability {
+			out[t] = 1
+		}
+	}
+	return out
+}
+
+// Insert adds a spike to each empty step with Probability.
+type Insert struct {
+	Probability float64
+}
+
+func (i *Insert) Apply(spikes []int, ran *deuron.Random) []int {
+	out := make([]int, len(spikes))
+	for t, spik := range spikes {
+		if spik != 0 || ran.Uniform() < i.Probability {
+			out[t] = 1
+		}
+	}
+	return out
+}
+
+// Pipeline applies transforms in order. Either it draws from a shared
+// generator, for example, its presenter's, or it owns one which is
+// reseeded from its seed and the presentation count every presentation.
+// The latter means presentation n gets the same noise no matter what
+// happened before it.
+type Pipeline struct {
+	Transforms []ITransform
+
+	ran *deuron.Random
+
+	reseed       bool
+	seed         int64
+	presentation int
+}
+
+// NewPipeline creates a pipeline drawing from ran.
+func NewPipeline(ran *deuron.Random, transforms ...ITransform) *Pipeline {
+	p := new(Pipeline)
+	p.Transforms = transforms
+	p.ran = ran
+	return p
+}
+
+// NewSeededPipeline creates a pipeline that is reseeded every
+// presentation.
+func NewSeededPipeline(seed int64, transforms ...ITransform) *Pipeline {
+	p := new(Pipeline)
+	p.Transforms = transforms
+	p.ran = deuron.NewRandom(seed)
+	p.reseed = true
+	p.seed = seed
+	return p
+}
+
+// Reseed restarts a seeded pipeline's presentations from seed.
+func (p *Pipeline) Reseed(seed int64) {
+	p.seed = seed
+	p.presentation = 0
+}
+
+// Apply transforms spikes for the next presentation.
+func (p *Pipeline) Apply(spikes []int) []int {
+	if p.reseed {
+		p.ran.Seed(p.seed + int64(p.presentation))
+	}
+	p.presentation++
+
+	out := spikes
+	for _, t := range p.Transforms {
+		out = t.Apply(out, p.ran)
+	}
+
+	if len(p.Transforms) == 0 {
+		out = make([]int, len(spikes))
+		copy(out, spikes)
+	}
+
+	return out
+}
+
+func (p *Pipeline) State() interface{} {
+	return map[string]interface{}{
+		"presentation": p.presentation,
+	}
+}
+
+func (p *Pipeline) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
+	p.presentation = int(jmap["presentation"].(float64))
+}
+
+// TransformSpec describes a transform in the patterns file. Type is one
+// of "stretch" (Factor), "uniform" (Width), "gauss" (Sigma), "delete"
+// (Probability) or "insert" (Probability).
+type TransformSpec struct {
+	Type        string
+	Factor      float64
+	Width       int
+	Sigma       float64
+	Probability float64
+}
+
+// Build creates the transform.
+func (ts *TransformSpec) Build() (ITransform, error) {
+	switch ts.Type {
+	case "stretch":
+		if ts.Factor <= 0 {
+			return nil, fmt.Errorf("stretch: Factor must be > 0")
+		}
+		return &Stretch{Factor: ts.Factor}, nil
+	case "uniform":
+		return &UniformJitter{Width: ts.Width}, nil
+	case "gauss":
+		return &GaussianJitter{Sigma: ts.Sigma}, nil
+	case "delete":
+		return &Delete{Probability: ts.Probability}, nil
+	case "insert":
+		return &Insert{Probability: ts.Probability}, nil
+	}
+
+	return nil, fmt.Errorf("unknown transform type (%s)", ts.Type)
+}
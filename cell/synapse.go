@@ -32,6 +32,11 @@ type ISynapse interface {
 	Load(json interface{})
 	ToJSON() interface{}
 
+	// State captures the dynamic state, for example, traces, for a
+	// checkpoint. RestoreState is the reverse.
+	State() interface{}
+	RestoreState(state interface{})
+
 	SetWeight(float64)
 	SetWMax(float64)
 	SetWMin(float64)
@@ -0,0 +1,104 @@
+package main
+
+// Runs the sim without the GUI and writes graphs as pngs, for example:
+//   go run ./cmd/graphs -graphs weight,psp -start 0 -end 1000 -lane 3 -width 2000 -height 400
+//   go run ./cmd/graphs -report report.png
+// Run it from the repo root so ./stimulus can be found.
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wdevore/Deuron5/deuron"
+	"github.com/wdevore/Deuron5/deuron/app/graphs"
+	"github.com/wdevore/Deuron5/simulation/runreset"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+func main() {
+	settings := flag.String("settings", "neuron.json", "app settings")
+	names := flag.String("graphs", strings.Join(graphs.ReportNames, ","), "comma separated graphs to write")
+	start := flag.Int("start", 0, "first sample")
+	end := flag.Int("end", 0, "last sample, default is the whole run")
+	lane := flag.Int("lane", 0, "synapse for the single synapse graphs")
+	width := flag.Int("width", 2000, "width of each graph")
+	height := flag.Int("height", 200, "height of each graph")
+	dir := flag.String("dir", ".", "directory each graph's <name>.png is written to")
+	runs := flag.Int("runs", 1, "runs, for the raster and psth graphs")
+	report := flag.String("report", "", "write every graph, one above the other, to this png instead")
+	flag.Parse()
+
+	byteValue, err := ioutil.ReadFile(*settings)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	jsonMap := make(map[string]interface{})
+	err = json.Unmarshal(byteValue, &jsonMap)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	deuron.SimModel.LoadSettings(jsonMap)
+
+	// The sim's context takes over its model's bus, so it gets a copy.
+	model := deuron.SimModel.Clone()
+
+	sim := runreset.NewHeadlessRunResetSim(model)
+	sim.Create()
+	for i := 0; i < *runs; i++ {
+		sim.RunPause()
+	}
+
+	// The graphs read the global model, for example, the weight bounds
+	// the sim loaded from the stimulus.
+	deuron.SimModel.Load(model.ToJSON())
+
+	// The graphs draw the global samples.
+	samples.Sim = sim.Samples()
+	samples.Runs = sim.Runs()
+
+	view := &graphs.View{Start: *start, End: *end, Lane: *lane}
+	if view.End <= 0 {
+		view.End = int(model.GetFloat("Samples"))
+	}
+
+	images := []*image.RGBA{}
+	for _, name := range strings.Split(*names, ",") {
+		img, err := graphs.Render(name, *width, *height, view)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if *report != "" {
+			images = append(images, img)
+			continue
+		}
+
+		fileName := filepath.Join(*dir, name+".png")
+		err = graphs.SavePNG(fileName, img)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote (%s)\n", fileName)
+	}
+
+	if *report != "" {
+		err = graphs.SavePNG(*report, graphs.Composite(images))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote (%s)\n", *report)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+// Runs an experiment protocol without the GUI, for example:
+//   go run ./cmd/protocol -protocol protocols/train_test.json -out phases.csv
+// Run it from the repo root so ./stimulus can be found.
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/wdevore/Deuron5/deuron"
+	"github.com/wdevore/Deuron5/simulation/protocol"
+)
+
+func main() {
+	settings := flag.String("settings", "neuron.json", "app settings the protocol starts from")
+	protocolFile := flag.String("protocol", "protocols/train_test.json", "protocol of timed phases")
+	outFile := flag.String("out", "", "csv of each phase's results, default is stdout")
+	checkpoint := flag.String("checkpoint", "", "optional checkpoint written at the end")
+	flag.Parse()
+
+	byteValue, err := ioutil.ReadFile(*settings)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	jsonMap := make(map[string]interface{})
+	err = json.Unmarshal(byteValue, &jsonMap)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	model := deuron.NewModel()
+	model.LoadSettings(jsonMap)
+
+	p, err := protocol.LoadProtocol(*protocolFile)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	sim, results := protocol.Run(p, model)
+
+	if *checkpoint != "" {
+		err = sim.Checkpoint(*checkpoint)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	out := os.Stdout
+	if *outFile != "" {
+		out, err = os.Create(*outFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		defer out.Close()
+	}
+
+	protocol.WriteCSV(out, results)
+}
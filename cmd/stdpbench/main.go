@@ -0,0 +1,77 @@
+package main
+
+// Measures the STDP window of the stimulus' synapse settings, for example:
+//   go run ./cmd/stdpbench -spec bench.json -out window.csv -png window.png
+// Run it from the repo root so ./stimulus can be found.
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/wdevore/Deuron5/deuron"
+	"github.com/wdevore/Deuron5/simulation/bench"
+)
+
+func main() {
+	settings := flag.String("settings", "neuron.json", "app settings, the stimulus gives the synapse settings")
+	specFile := flag.String("spec", "", "bench spec, default is +/-100ms at 1Hz")
+	outFile := flag.String("out", "", "csv of the window, default is stdout")
+	pngFile := flag.String("png", "", "optional plot of the window")
+	flag.Parse()
+
+	byteValue, err := ioutil.ReadFile(*settings)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	jsonMap := make(map[string]interface{})
+	err = json.Unmarshal(byteValue, &jsonMap)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	model := deuron.NewModel()
+	model.LoadSettings(jsonMap)
+
+	spec := bench.DefaultSpec()
+	if *specFile != "" {
+		spec, err = bench.LoadSpec(*specFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	b, err := bench.NewBench(spec, model)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	points := b.Run()
+
+	if *pngFile != "" {
+		err = bench.SavePNG(*pngFile, points, 1024, 600)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	out := os.Stdout
+	if *outFile != "" {
+		out, err = os.Create(*outFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		defer out.Close()
+	}
+
+	bench.WriteCSV(out, points)
+}
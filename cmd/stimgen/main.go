@@ -0,0 +1,38 @@
+package main
+
+// Generates a random stimulus and its settings, for example:
+//   go run ./cmd/stimgen -name rand_1 -lanes 10 -length 25 -spikes 5 -patterns 3 -overlap 0.3 -seed 7
+// writes stimulus/rand_1.patterns.json and stimulus/rand_1.json.
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/wdevore/Deuron5/cell/stimulus"
+)
+
+func main() {
+	spec := new(stimulus.GeneratorSpec)
+
+	flag.StringVar(&spec.Name, "name", "", "stimulus name, the files are <name>.patterns.json and <name>.json")
+	flag.IntVar(&spec.Lanes, "lanes", 10, "lanes per pattern")
+	flag.IntVar(&spec.Length, "length", 25, "length of each lane")
+	flag.IntVar(&spec.SpikesPerLane, "spikes", 5, "spikes per lane")
+	flag.IntVar(&spec.Patterns, "patterns", 1, "number of distinct patterns")
+	flag.Float64Var(&spec.Overlap, "overlap", 0.0, "fraction of lanes each pattern shares with the first")
+	flag.Int64Var(&spec.Seed, "seed", 1963, "random seed")
+	flag.IntVar(&spec.Synapses, "synapses", 0, "synapses in the settings file, default is lanes")
+	flag.IntVar(&spec.Interval, "interval", 0, "ms from one pattern's onset to the next's, default is twice an expanded pattern")
+	dir := flag.String("dir", "./stimulus/", "output directory")
+	flag.Parse()
+
+	sf, err := stimulus.WriteGenerated(*dir, spec)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Generated stimulus (%s): %s\n", filepath.Join(*dir, spec.Name), sf.Description)
+}
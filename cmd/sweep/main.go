@@ -0,0 +1,63 @@
+package main
+
+// Runs a parameter sweep without the GUI, for example:
+//   go run ./cmd/sweep -spec sweep.json -out results.csv
+// Run it from the repo root so ./stimulus can be found.
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/wdevore/Deuron5/deuron"
+	"github.com/wdevore/Deuron5/simulation/sweep"
+)
+
+func main() {
+	settings := flag.String("settings", "neuron.json", "app settings the sweep starts from")
+	specFile := flag.String("spec", "sweep.json", "sweep spec")
+	outFile := flag.String("out", "", "csv results file, default is stdout")
+	flag.Parse()
+
+	byteValue, err := ioutil.ReadFile(*settings)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	jsonMap := make(map[string]interface{})
+	err = json.Unmarshal(byteValue, &jsonMap)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	model := deuron.NewModel()
+	model.LoadSettings(jsonMap)
+
+	spec, err := sweep.LoadSpec(*specFile)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	results, err := sweep.NewSweep(spec, model).Run()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	out := os.Stdout
+	if *outFile != "" {
+		out, err = os.Create(*outFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		defer out.Close()
+	}
+
+	sweep.WriteCSV(out, spec.Keys(), results)
+}
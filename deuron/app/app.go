@@ -3,6 +3,7 @@ package app
 import (
 	"encoding/json"
 	"fmt"
+	"image"
 	"io/ioutil"
 	"log"
 	"os"
@@ -15,16 +16,28 @@ import (
 	"github.com/wdevore/Deuron5/deuron/app/events"
 	"github.com/wdevore/Deuron5/deuron/app/graphs"
 	"github.com/wdevore/Deuron5/deuron/app/gui"
+	"github.com/wdevore/Deuron5/simulation/bench"
 	"github.com/wdevore/Deuron5/simulation/runreset"
 	"github.com/wdevore/Deuron5/simulation/samples"
 )
 
 const (
+	// The window's size if the display is big enough. It can be resized.
 	width  = 1000 * 2
 	height = 800 * 2
 	xpos   = 100
 	ypos   = 0
-	FPS    = 30 // 60 or 30 fps
+
+	// Where the graphs begin, below the panels.
+	graphsTop = 75
+	FPS       = 30 // 60 or 30 fps
+
+	// Pixels the arrow keys scroll the graphs.
+	scrollStep = 50
+
+	// Range of the Scale.
+	minScale = 0.5
+	maxScale = 3.0
 )
 
 // App shows the plots and graphs.
@@ -57,7 +70,31 @@ type App struct {
 
 	dirty bool
 
-	// expoGraph   graphs.IGraph
+	// The STDP bench's learning window, shown over the other graphs.
+	stdpGraph *graphs.STDPGraph
+	// The points of a bench running in the background, nil when none is.
+	benchDone chan []*bench.Point
+
+	// Zooms and pans the graphs with the mouse.
+	navigator *graphs.Navigator
+
+	// The graphs and panel fields, from layout.json or DefaultLayout.
+	layout *Layout
+
+	// The content is laid out viewWidth by viewHeight, the window's size in
+	// points divided by the Scale.
+	scale                 float64
+	viewWidth, viewHeight int32
+
+	// Where each graph goes, unscrolled, and how far down the last one
+	// goes. Graphs past the bottom of the view are scrolled to.
+	places       []sdl.Point
+	graphsBottom int32
+	scrollY      int32
+
+	// The window or the Scale changed, the view is fitted to it before
+	// the next draw.
+	resized bool
 
 	//keymapBar *KeymapBar
 	gui *gui.Gui
@@ -68,6 +105,18 @@ type App struct {
 	// comm channel to simulation
 	statusComm chan string
 
+	// A run is going on in the background, the graphs follow it. Where
+	// it's at is shown as the status.
+	live     bool
+	runState deuron.SimState
+	epochs   int
+
+	// How far the sim's samples are copied to samples.Sim, for the run
+	// and epoch it was.
+	shown       int
+	shownSams   *samples.SamplesCollection
+	shownEpochs int
+
 	// simulation = run_reset.go
 	simulation deuron.ISimulation
 
@@ -83,12 +132,12 @@ type App struct {
 	// Keymap fields
 	// -----------------------------------------------------------------
 	// Current mouse position
-	mouseX, mouseY  int32
-	controlID       string
-	controlMsg      string
-	controlOrgValue string
-	controlField    string
-	currentValue    string
+	mouseX, mouseY int32
+	controlID      string
+
+	// The value button being edited, keys go to it rather than being
+	// commands.
+	editID string
 
 	jsonMap map[string]interface{}
 }
@@ -108,6 +157,14 @@ func NewApp() *App {
 func (ap *App) Open() {
 	ap.Load("neuron.json")
 
+	var err error
+	ap.layout, err = LoadLayout("layout.json")
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("Using the built in layout.")
+		ap.layout = DefaultLayout()
+	}
+
 	ap.initialize()
 
 	ap.shuttingDown = false
@@ -136,6 +193,16 @@ func (ap *App) Handle(vx, vy int32, eventType events.MouseEventType) (handled bo
 		return true
 	}
 
+	// Graphs scrolled up under the panels can't be clicked there.
+	if vy < graphsTop && eventType != events.MouseMotion && eventType != events.MouseButtonUp && eventType != events.MouseRightButtonUp {
+		return false
+	}
+
+	eventType, handled = ap.navigator.Handle(vx, vy, eventType)
+	if handled {
+		return true
+	}
+
 	it := ap.graphs.Iterator()
 	for it.Next() {
 		widget := it.Value().(gui.IWidget)
@@ -157,6 +224,9 @@ func (ap *App) Listen(msg *comm.MessageEvent) {
 		case "Data", "Button", "ToggleButton":
 			switch msg.Action {
 			case "Changed":
+				if msg.Field == "Scale" {
+					ap.resized = true
+				}
 				ap.dirty = true
 				break
 			}
@@ -189,24 +259,52 @@ func (ap *App) Listen(msg *comm.MessageEvent) {
 		case "Edit":
 			switch msg.Message {
 			case "Start":
-				ap.controlID = msg.ID
-				ap.controlField = msg.Field
-				ap.controlMsg = msg.Message
-				ap.controlOrgValue = msg.Value
-				ap.currentValue = ""
+				// Only one value button is edited at a time.
+				if ap.editID != "" && ap.editID != msg.ID {
+					comm.MsgBus.Send2("App", "Control", "Edit", "Cancel", ap.editID, "")
+				}
+				ap.editID = msg.ID
+				sdl.StartTextInput()
+				ap.dirty = true
+				return
+			case "End":
+				ap.editID = ""
+				sdl.StopTextInput()
 				ap.dirty = true
 				return
 			}
 			break
 		case "Command":
 			switch msg.Message {
+			case "Run":
+				ap.Create()
+				ap.RunSim()
+				ap.dirty = true
+				return
 			case "RunPause":
-				// fmt.Println("---|||| Running and pausing simulation ||||---")
-				// Run a single pass through a complete simulation then pause.
 				ap.Create()
 				ap.RunPause()
 				ap.dirty = true
 				return
+			case "Pause":
+				ap.Pause()
+				ap.dirty = true
+				return
+			case "Resume":
+				ap.Resume()
+				ap.dirty = true
+				return
+			case "Stop":
+				ap.Stop()
+				ap.dirty = true
+				return
+			case "Snapshot":
+				ap.snapshot()
+				return
+			case "STDPBench":
+				ap.toggleSTDPBench()
+				ap.dirty = true
+				return
 			case "Save":
 				ap.Save("neuron.json")
 				return
@@ -222,7 +320,6 @@ func (ap *App) Listen(msg *comm.MessageEvent) {
 		case "Selected":
 			// Unselect other graphs
 			ap.controlID = msg.ID
-			ap.currentValue = ""
 			ap.dirty = true
 			return
 		case "UnSelected":
@@ -259,6 +356,14 @@ func (ap *App) Run() {
 	for ap.running {
 		sdl.PumpEvents()
 
+		ap.pollHead()
+		ap.pollBench()
+
+		if ap.resized {
+			ap.reflow()
+			ap.resized = false
+		}
+
 		if ap.dirty {
 			ap.Update()
 			ap.dirty = false
@@ -280,6 +385,10 @@ func (ap *App) Update() {
 
 	ap.updateGraphs()
 
+	if ap.stdpGraph.Check() {
+		ap.stdpGraph.Draw()
+	}
+
 	ap.txtSimStatus.Draw()
 	ap.txtTime.Draw()
 	ap.txtActiveProperty.Draw()
@@ -352,11 +461,26 @@ func (ap *App) initialize() {
 		panic(err)
 	}
 
-	ap.window, err = sdl.CreateWindow("Deuron5 Graph", xpos, ypos, width, height, sdl.WINDOW_SHOWN)
+	// No bigger than the display, for example, a laptop's.
+	w, h := int32(width), int32(height)
+	bounds, err := sdl.GetDisplayUsableBounds(0)
+	if err == nil {
+		if bounds.W < w {
+			w = bounds.W
+		}
+		if bounds.H < h {
+			h = bounds.H
+		}
+	}
+
+	ap.window, err = sdl.CreateWindow("Deuron5 Graph", xpos, ypos, w, h, sdl.WINDOW_SHOWN|sdl.WINDOW_RESIZABLE|sdl.WINDOW_ALLOW_HIGHDPI)
 	if err != nil {
 		panic(err)
 	}
 
+	// Text is only typed while a value button is edited.
+	sdl.StopTextInput()
+
 	ap.surface, err = ap.window.GetSurface()
 	if err != nil {
 		panic(err)
@@ -368,12 +492,100 @@ func (ap *App) initialize() {
 		panic(err)
 	}
 
-	ap.texture, err = ap.renderer.CreateTexture(sdl.PIXELFORMAT_ABGR8888, sdl.TEXTUREACCESS_STREAMING, width, height)
+	ap.fitWindow()
+}
+
+// fitWindow sizes the view, and the texture it's drawn to, to the window
+// at the Scale.
+func (ap *App) fitWindow() {
+	ap.scale = deuron.SimModel.GetFloat("Scale")
+	if ap.scale <= 0 {
+		ap.scale = 1
+	}
+
+	winW, winH := ap.window.GetSize()
+
+	// A high-DPI display has more pixels than the window has points.
+	dpi := 1.0
+	outW, _, err := ap.renderer.GetOutputSize()
+	if err == nil && winW > 0 {
+		dpi = float64(outW) / float64(winW)
+	}
+	ap.renderer.SetScale(float32(dpi*ap.scale), float32(dpi*ap.scale))
+
+	ap.viewWidth = int32(float64(winW) / ap.scale)
+	ap.viewHeight = int32(float64(winH) / ap.scale)
+
+	if ap.texture != nil {
+		ap.texture.Destroy()
+	}
+
+	ap.texture, err = ap.renderer.CreateTexture(sdl.PIXELFORMAT_ABGR8888, sdl.TEXTUREACCESS_STREAMING, ap.viewWidth, ap.viewHeight)
 	if err != nil {
 		panic(err)
 	}
 }
 
+// reflow fits the graphs and panels to a resized window or a new Scale.
+func (ap *App) reflow() {
+	ap.fitWindow()
+
+	// Graphs are made for a width, so they're made again. Which one is
+	// selected is forgotten.
+	it := ap.graphs.Iterator()
+	for it.Next() {
+		graph := it.Value().(graphs.IGraph)
+		comm.MsgBus.Unsubscribe(graph.(comm.IMessageListener))
+		graph.Destroy()
+	}
+	ap.controlID = ""
+
+	ap.createGraphs()
+
+	it = ap.graphs.Iterator()
+	for it.Next() {
+		comm.MsgBus.Subscribe(it.Value().(comm.IMessageListener))
+	}
+
+	ap.stdpGraph.SetGraphics(ap.renderer, ap.texture)
+
+	ap.layoutView()
+}
+
+// layoutView places the panels, and the graphs below them, in the view.
+func (ap *App) layoutView() {
+	ap.gui.Resize(ap.texture, ap.viewWidth)
+
+	graphs.SetViewport(sdl.Rect{X: 0, Y: graphsTop, W: ap.viewWidth, H: ap.viewHeight - graphsTop})
+
+	ap.scroll(0)
+}
+
+// scroll moves the graphs up by dy, down if it's negative, no further
+// than the first or last is in view.
+func (ap *App) scroll(dy int32) {
+	ap.scrollY += dy
+	if ap.scrollY > ap.graphsBottom-ap.viewHeight {
+		ap.scrollY = ap.graphsBottom - ap.viewHeight
+	}
+	if ap.scrollY < 0 {
+		ap.scrollY = 0
+	}
+
+	it := ap.graphs.Iterator()
+	for it.Next() {
+		p := ap.places[it.Index()]
+		it.Value().(gui.IWidget).SetPos(p.X, p.Y-ap.scrollY)
+	}
+
+	ap.dirty = true
+}
+
+// toView maps a window position, in points, to the view.
+func (ap *App) toView(x, y int32) (int32, int32) {
+	return int32(float64(x) / ap.scale), int32(float64(y) / ap.scale)
+}
+
 // Configure view with draw objects
 func (ap *App) Configure() {
 	fmt.Println("App configuring...")
@@ -381,6 +593,12 @@ func (ap *App) Configure() {
 	ap.createGraphs()
 	ap.createGui()
 
+	ap.stdpGraph = graphs.NewSTDPGraph(ap.renderer, ap.texture, 1024, 600).(*graphs.STDPGraph)
+	ap.stdpGraph.SetName("STDP window")
+	ap.stdpGraph.SetPos(488, 400)
+
+	ap.layoutView()
+
 	sdl.SetEventFilterFunc(ap.filterEvent, nil)
 
 	// sdl.SetHint(sdl.HINT_RENDER_SCALE_QUALITY, "linear")
@@ -405,7 +623,7 @@ func (ap *App) Configure() {
 }
 
 func (ap *App) createGui() {
-	ap.gui = gui.NewGui(ap.renderer, ap.texture, 1000, 50)
+	ap.gui = gui.NewGui(ap.renderer, ap.texture, 1000, 50, ap.layout.Panels)
 }
 
 // --------------------------------------------------------------------
@@ -414,97 +632,45 @@ func (ap *App) createGui() {
 
 func (ap *App) createGraphs() {
 	ap.graphs = sll.New()
+	ap.places = nil
 
+	// Graphs are stacked below the panels a row at a time, as wide as the
+	// view unless they have a Width. A row is as tall as its tallest
+	// graph, one that doesn't fit the row starts another.
 	graphIDs := 10000
-	graph := graphs.NewStimulusScatterGraph(ap.renderer, ap.texture, 2000, 100)
-	graph.SetName("Stimulus")
-	widget := graph.(gui.IWidget)
-	widget.SetID(graphIDs)
-	widget.SetPos(0, 75)
-	ap.graphs.Add(graph)
-
-	_, y := widget.Position()
-	graphIDs++
-	graph = graphs.NewSurgeGraph(ap.renderer, ap.texture, 2000, 200)
-	graph.SetName("Synapse Surge")
-	widget = graph.(gui.IWidget)
-	widget.SetID(graphIDs)
-	widget.SetPos(0, y+100)
-	ap.graphs.Add(graph)
-
-	_, y = widget.Position()
-	graphIDs++
-	graph = graphs.NewPspGraph(ap.renderer, ap.texture, 2000, 200)
-	graph.SetName("Synapse PSP")
-	widget = graph.(gui.IWidget)
-	widget.SetID(graphIDs)
-	widget.SetPos(0, y+200)
-	ap.graphs.Add(graph)
-
-	_, y = widget.Position()
-	graphIDs++
-	graph = graphs.NewWeightGraph(ap.renderer, ap.texture, 2000, 200)
-	graph.SetName("Synapse Weight")
-	widget = graph.(gui.IWidget)
-	widget.SetID(graphIDs)
-	widget.SetPos(0, y+200)
-	ap.graphs.Add(graph)
-
-	_, y = widget.Position()
-	graphIDs++
-	graph = graphs.NewNeuronPspGraph(ap.renderer, ap.texture, 2000, 200)
-	graph.SetName("Neuron PSP")
-	widget = graph.(gui.IWidget)
-	widget.SetID(graphIDs)
-	widget.SetPos(0, y+200)
-	ap.graphs.Add(graph)
-
-	_, y = widget.Position()
-	graphIDs++
-	graph = graphs.NewPostSpikeGraph(ap.renderer, ap.texture, 2000, 50)
-	graph.SetName("Post Spike")
-	widget = graph.(gui.IWidget)
-	widget.SetID(graphIDs)
-	widget.SetPos(0, y+200)
-	ap.graphs.Add(graph)
-
-	_, y = widget.Position()
-	graphIDs++
-	graph = graphs.NewNeuronAPGraph(ap.renderer, ap.texture, 2000, 100)
-	graph.SetName("Neuron AP fast")
-	widget = graph.(gui.IWidget)
-	widget.SetID(graphIDs)
-	widget.SetPos(0, y+50)
-	ap.graphs.Add(graph)
-
-	_, y = widget.Position()
-	graphIDs++
-	graph = graphs.NewNeuronAPSlowGraph(ap.renderer, ap.texture, 2000, 100)
-	graph.SetName("Neuron AP slow")
-	widget = graph.(gui.IWidget)
-	widget.SetID(graphIDs)
-	widget.SetPos(0, y+100)
-	ap.graphs.Add(graph)
-
-	// _, y = widget.Position()
-	// graphIDs++
-	// graph = graphs.NewDTGraph(ap.renderer, ap.texture, 2000, 50)
-	// graph.SetName("dt")
-	// widget = graph.(gui.IWidget)
-	// widget.SetID(graphIDs)
-	// widget.SetPos(0, y+200)
-	// ap.graphs.Add(graph)
-
-	// _, y = widget.Position()
-	// graphIDs++
-	// graph = graphs.NewNeuronDtGraph(ap.renderer, ap.texture, 2000, 50)
-	// graph.SetName("Neuron dt")
-	// widget = graph.(gui.IWidget)
-	// widget.SetID(graphIDs)
-	// widget.SetPos(0, y+50)
-	// ap.graphs.Add(graph)
-
-	// ap.expoGraph = graphs.NewExpoGraph(ap.renderer, ap.texture, 1024, 600)
+	x, y := int32(0), int32(graphsTop)
+	rowWidth, rowHeight := int32(0), int32(0)
+
+	for _, spec := range ap.layout.Graphs {
+		if spec.Hidden {
+			continue
+		}
+
+		graph := spec.Create(ap.renderer, ap.texture, int(ap.viewWidth))
+		w, h := graph.(graphs.ITimeGraph).Size()
+
+		if spec.SameRow && rowWidth+w <= ap.viewWidth {
+			x = rowWidth
+		} else {
+			x = 0
+			y += rowHeight
+			rowHeight = 0
+		}
+		rowWidth = x + w
+		if h > rowHeight {
+			rowHeight = h
+		}
+
+		widget := graph.(gui.IWidget)
+		widget.SetID(graphIDs)
+		widget.SetPos(x, y)
+		ap.graphs.Add(graph)
+		ap.places = append(ap.places, sdl.Point{X: x, Y: y})
+		graphIDs++
+	}
+	ap.graphsBottom = y + rowHeight
+
+	ap.navigator = graphs.NewNavigator(ap.graphs)
 }
 
 func (ap *App) updateGraphs() {
@@ -512,6 +678,14 @@ func (ap *App) updateGraphs() {
 		it := ap.graphs.Iterator()
 		for it.Next() {
 			graph := it.Value().(graphs.IGraph)
+
+			// Graphs scrolled out of view aren't drawn.
+			_, y := graph.(gui.IWidget).Position()
+			_, h := graph.(graphs.ITimeGraph).Size()
+			if y+h <= graphsTop || y >= ap.viewHeight {
+				continue
+			}
+
 			draw := graph.Check()
 			if draw {
 				graph.Draw()
@@ -544,25 +718,7 @@ func (ap *App) Load(file string) {
 
 	// Directly load model rather than send messages to listeners
 	// who don't exist yet.
-	m := deuron.SimModel
-
-	// Calculate sim-duration (aka sample size) based on TimeStep and duration
-	duration := ap.jsonMap["Duration"].(float64)
-	timeStep := ap.jsonMap["TimeStep"].(float64)
-	simDuration := duration * 1000000.0 / timeStep
-	fmt.Printf("Sample size: %d\n", int(simDuration))
-
-	m.SetFloat("TimeStep", timeStep) // microseconds
-	m.SetFloat("Duration", duration) // seconds
-	m.SetFloat("Samples", simDuration)
-
-	m.SetFloat("Range_Start", ap.jsonMap["RangeStart"].(float64))
-	m.SetFloat("Range_End", ap.jsonMap["RangeEnd"].(float64))
-	m.SetString("Stimulus", ap.jsonMap["Stimulus"].(string))
-	m.SetFloat("Synapse_Count", ap.jsonMap["Synapse_Count"].(float64))
-
-	m.SetFloat("weightMin", ap.jsonMap["weightMin"].(float64))
-	m.SetFloat("weightMax", ap.jsonMap["weightMax"].(float64))
+	deuron.SimModel.LoadSettings(ap.jsonMap)
 
 	fmt.Println("Loaded")
 }
@@ -595,6 +751,7 @@ func (ap *App) saveSimSettings(file string) {
 		"TimeStep":      mo.GetFloat("TimeStep"),
 		"Synapse_Count": mo.GetFloat("Synapse_Count"),
 		"Stimulus":      mo.GetString("Stimulus"),
+		"Seed":          mo.GetFloat("Seed"),
 	}
 
 	jsonString, err := json.MarshalIndent(m, "", "  ")
@@ -666,13 +823,169 @@ func (ap *App) Create() {
 	go ap.pollForMessage()
 }
 
+// snapshot writes every graph, as currently shown, one above the other
+// to a png.
+func (ap *App) snapshot() {
+	images := []*image.RGBA{}
+
+	it := ap.graphs.Iterator()
+	for it.Next() {
+		graph := it.Value().(graphs.IGraph)
+		img, err := graphs.Snapshot(graph)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		images = append(images, img)
+	}
+
+	if len(images) == 0 {
+		fmt.Println("Nothing to snapshot, run the simulation first.")
+		return
+	}
+
+	fileName := fmt.Sprintf("snapshot_%s.png", time.Now().Format("20060102_150405"))
+	err := graphs.SavePNG(fileName, graphs.Composite(images))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Printf("Wrote snapshot (%s)\n", fileName)
+}
+
+// toggleSTDPBench measures the learning window with the current synapse
+// values, in the background, and shows it, or hides it if it is showing.
+func (ap *App) toggleSTDPBench() {
+	if ap.benchDone != nil {
+		fmt.Println("The STDP bench is already running.")
+		return
+	}
+
+	if ap.stdpGraph.Visible() {
+		ap.stdpGraph.SetVisible(false)
+		return
+	}
+
+	spec := bench.DefaultSpec()
+	spec.Frequencies = []float64{1.0, 10.0, 20.0}
+
+	// Once created the model has the stimulus' values and any edits.
+	if ap.created {
+		spec.Overrides = bench.ModelOverrides(deuron.SimModel)
+	}
+
+	// A copy, edits made while it runs don't change it part way.
+	b, err := bench.NewBench(spec, deuron.SimModel.Clone())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println("Running STDP bench...")
+	done := make(chan []*bench.Point, 1)
+	ap.benchDone = done
+	go func() {
+		done <- b.Run()
+	}()
+}
+
+// pollBench shows the learning window once the bench is done.
+func (ap *App) pollBench() {
+	if ap.benchDone == nil {
+		return
+	}
+
+	select {
+	case points := <-ap.benchDone:
+		ap.benchDone = nil
+		ap.stdpGraph.SetPoints(points)
+		ap.stdpGraph.SetVisible(true)
+		ap.dirty = true
+		fmt.Println("STDP bench done.")
+	default:
+	}
+}
+
 func (ap *App) Step() {
 	ap.simulation.Step()
 }
 
+// RunSim runs the sim in the background, an epoch or, with AutoRun on,
+// epochs until Stop. The graphs follow it at the frame rate.
+func (ap *App) RunSim() {
+	if ap.simulation.StartRun() {
+		ap.live = true
+	}
+}
+
+// RunPause runs, pauses or resumes, depending on where the run is at.
 func (ap *App) RunPause() {
-	// Once AutoSync is complete we can run in a "go" routine.
-	ap.simulation.RunPause()
+	switch ap.simulation.RunState() {
+	case deuron.SimIdle:
+		ap.RunSim()
+	case deuron.SimRunning:
+		ap.simulation.PauseRun()
+	case deuron.SimPaused:
+		ap.simulation.ResumeRun()
+	}
+}
+
+// pollHead redraws, at most once a frame, up to the newest step of a
+// running sim and shows where the run is at.
+func (ap *App) pollHead() {
+	if ap.simulation == nil {
+		return
+	}
+
+	select {
+	case t := <-ap.simulation.Head():
+		ap.show(t)
+		ap.live = true
+	default:
+	}
+
+	// An epoch ending changes the runs the graphs show too.
+	state, epochs := ap.simulation.RunState(), ap.simulation.Epochs()
+	if state != ap.runState || epochs != ap.epochs {
+		ap.runState, ap.epochs = state, epochs
+
+		status := state.String()
+		if epochs > 0 {
+			status = fmt.Sprintf("%s, epoch %d", status, epochs)
+		}
+		ap.txtSimStatus.SetValue(status)
+		ap.dirty = true
+	}
+
+	if ap.live && state == deuron.SimIdle {
+		// The steps since the last frame.
+		select {
+		case t := <-ap.simulation.Head():
+			ap.show(t)
+		default:
+		}
+		ap.live = false
+		graphs.SetHead(-1)
+		ap.dirty = true
+	}
+}
+
+// show copies the sim's samples up to the head t into samples.Sim, which
+// the graphs draw, and moves the graphs' head there. Only the steps since
+// the last frame are copied, unless a new run or epoch started.
+func (ap *App) show(t float64) {
+	end := int(t) + 1
+	epochs := ap.simulation.Epochs()
+	if !ap.live || samples.Sim != ap.shownSams || epochs != ap.shownEpochs || end < ap.shown {
+		ap.shown = 0
+	}
+	ap.simulation.Samples().CopyTo(samples.Sim, ap.shown, end)
+	ap.shown, ap.shownSams, ap.shownEpochs = end, samples.Sim, epochs
+
+	graphs.SetHead(t)
+	ap.txtTime.SetValue(fmt.Sprintf("(%0.1f)", t))
+	ap.dirty = true
 }
 
 func (ap *App) Reset() {
@@ -680,6 +993,21 @@ func (ap *App) Reset() {
 }
 
 func (ap *App) Pause() {
+	if ap.simulation != nil {
+		ap.simulation.PauseRun()
+	}
+}
+
+func (ap *App) Resume() {
+	if ap.simulation != nil {
+		ap.simulation.ResumeRun()
+	}
+}
+
+func (ap *App) Stop() {
+	if ap.simulation != nil {
+		ap.simulation.StopRun()
+	}
 }
 
 // Command handles messages from the console.
@@ -704,10 +1032,7 @@ func (ap *App) Command(args []string) {
 			panic("Not connected. Please connect first. Use 'help'.")
 		}
 		ap.simulation.Send("stop")
-		response := <-ap.statusComm
-		if response == "Stopped" {
-			fmt.Println("Sim requested to stop")
-		}
+		fmt.Println("Sim requested to stop")
 		break
 	case "prop":
 		// A command relating to a property
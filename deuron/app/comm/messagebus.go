@@ -62,6 +62,16 @@ func (mb *MessageBus) Subscribe(listener IMessageListener) {
 	mb.listeners = append(mb.listeners, listener)
 }
 
+// Unsubscribe stops listener getting messages.
+func (mb *MessageBus) Unsubscribe(listener IMessageListener) {
+	for i, l := range mb.listeners {
+		if l == listener {
+			mb.listeners = append(mb.listeners[:i], mb.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
 func (mb *MessageBus) SendEvent(e MessageEvent) {
 	me := new(MessageEvent)
 	me.Source = e.Source
@@ -4,6 +4,12 @@ type MouseEventType int
 
 const (
 	MouseMotion MouseEventType = iota
+	// Left button pressed
 	MouseButton
 	MouseWheel
+	MouseButtonUp
+	MouseRightButton
+	MouseRightButtonUp
+	MouseWheelUp
+	MouseWheelDown
 )
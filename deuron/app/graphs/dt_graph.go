@@ -8,7 +8,6 @@ import (
 
 	sll "github.com/emirpasic/gods/lists/singlylinkedlist"
 	"github.com/veandco/go-sdl2/sdl"
-	"github.com/wdevore/Deuron5/deuron"
 	"github.com/wdevore/Deuron5/deuron/app/comm"
 	"github.com/wdevore/Deuron5/deuron/app/gui"
 	"github.com/wdevore/Deuron5/simulation/samples"
@@ -170,7 +169,7 @@ func (g *DTGraph) Check() bool {
 	g.graphIt = lanes.Iterator()
 
 	if g.graphIt.First() {
-		activeSynID := int(deuron.SimModel.GetFloat("Active_Synapse"))
+		activeSynID := g.activeSynapse()
 		lane, _ := lanes.Get(activeSynID)
 		g.activeLane = lane.(*samples.SamplesLane)
 		g.setScanWindow(samples.Sim.DtSamples)
@@ -181,14 +180,7 @@ func (g *DTGraph) Check() bool {
 }
 
 func (g *DTGraph) setScanWindow(lanes *samples.Samples) {
-	// If RangeSync is enabled then we use the Model's range
-	sync := deuron.SimModel.GetFloat("RangeSync")
-	if sync == 1 {
-		g.scanStart = int(deuron.SimModel.GetFloat("Range_Start"))
-		g.scanEnd = int(deuron.SimModel.GetFloat("Range_End"))
-	} else {
-		g.scanStart, g.scanEnd = lanes.GetRange()
-	}
+	g.scanStart, g.scanEnd = g.scanRange(lanes)
 	g.scanIdx = g.scanStart
 }
 
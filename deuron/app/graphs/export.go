@@ -0,0 +1,122 @@
+package graphs
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+// Graphs can be drawn without a display: created without a renderer or
+// texture they only draw into their own image. Only png is written, gg
+// rasterizes everything so there isn't anything vector to write as svg.
+
+// exportable is how to create a graph and the title the app gives it.
+type exportable struct {
+	title  string
+	create func(*sdl.Renderer, *sdl.Texture, int, int) IGraph
+}
+
+// exportables by the names used for exports.
+var exportables = map[string]exportable{
+	"stimulus":  {"Stimulus", NewStimulusScatterGraph},
+	"surge":     {"Synapse Surge", NewSurgeGraph},
+	"psp":       {"Synapse PSP", NewPspGraph},
+	"weight":    {"Synapse Weight", NewWeightGraph},
+	"neuronpsp": {"Neuron PSP", NewNeuronPspGraph},
+	"postspike": {"Post Spike", NewPostSpikeGraph},
+	"apfast":    {"Neuron AP fast", NewNeuronAPGraph},
+	"apslow":    {"Neuron AP slow", NewNeuronAPSlowGraph},
+	"dt":        {"dt", NewDTGraph},
+	"neurondt":  {"Neuron dt", NewNeuronDtGraph},
+	"raster":    {"Post Spike Raster", NewRasterGraph},
+	"psth":      {"PSTH", NewPSTHGraph},
+	"whist":     {"Weight Histogram", NewWeightHistogramGraph},
+	"wheat":     {"Weight Heatmap", NewWeightHeatmapGraph},
+	"dwdt":      {"dw vs dt", newScatter("dt", "dw")},
+	"apphase":   {"AP fast vs slow", newScatter("apslow", "apfast")},
+}
+
+// newScatter creates scatter graphs of x and y.
+func newScatter(x, y string) func(*sdl.Renderer, *sdl.Texture, int, int) IGraph {
+	return func(renderer *sdl.Renderer, texture *sdl.Texture, width, height int) IGraph {
+		return NewScatterGraph(renderer, texture, width, height, x, y)
+	}
+}
+
+// ReportNames are the graphs of a report in the order the app shows them.
+var ReportNames = []string{"stimulus", "surge", "psp", "weight", "neuronpsp", "postspike", "raster", "psth", "whist", "wheat", "dwdt", "apphase", "apfast", "apslow"}
+
+// Render draws the named graph offscreen at any size. A nil view follows
+// the model's range and active synapse like the app does.
+func Render(name string, width, height int, view *View) (*image.RGBA, error) {
+	e, ok := exportables[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown graph (%s)", name)
+	}
+
+	if samples.Sim == nil {
+		return nil, fmt.Errorf("graph (%s): there aren't any samples", name)
+	}
+
+	graph := e.create(nil, nil, width, height)
+	graph.SetName(e.title)
+	graph.SetView(view)
+
+	return Snapshot(graph)
+}
+
+// Snapshot draws a graph and returns a copy of the image.
+func Snapshot(graph IGraph) (*image.RGBA, error) {
+	if !graph.Check() {
+		return nil, fmt.Errorf("graph (%s) has nothing to draw", graph.Name())
+	}
+
+	graph.Draw()
+
+	img := graph.Image()
+	c := image.NewRGBA(img.Bounds())
+	draw.Draw(c, c.Bounds(), img, img.Bounds().Min, draw.Src)
+
+	return c, nil
+}
+
+// Composite stacks images one above the other, for example, to make a
+// report of every graph.
+func Composite(images []*image.RGBA) *image.RGBA {
+	width, height := 0, 0
+	for _, img := range images {
+		if img.Bounds().Dx() > width {
+			width = img.Bounds().Dx()
+		}
+		height += img.Bounds().Dy()
+	}
+
+	c := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(c, c.Bounds(), &image.Uniform{color.RGBA{64, 64, 64, 255}}, image.Point{}, draw.Src)
+
+	y := 0
+	for _, img := range images {
+		r := image.Rect(0, y, img.Bounds().Dx(), y+img.Bounds().Dy())
+		draw.Draw(c, r, img, img.Bounds().Min, draw.Src)
+		y += img.Bounds().Dy()
+	}
+
+	return c
+}
+
+// SavePNG writes an image as a png.
+func SavePNG(fileName string, img image.Image) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return png.Encode(f, img)
+}
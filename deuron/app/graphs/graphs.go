@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"math"
 	"math/rand"
 	"strconv"
 
@@ -33,6 +34,21 @@ type IGraph interface {
 	MarkDirty(dirty bool)
 	SetName(string)
 	Name() string
+
+	// SetView fixes the range and lane shown rather than following the
+	// model, for example, for an export. nil follows the model again.
+	SetView(view *View)
+
+	// Image is what the last Draw rendered.
+	Image() *image.RGBA
+}
+
+// View is a range of samples and the lane, for graphs of a single
+// synapse, to show.
+type View struct {
+	Start int
+	End   int
+	Lane  int
 }
 
 type BaseGraph struct {
@@ -57,6 +73,7 @@ type BaseGraph struct {
 	maxTextColor     color.RGBA
 	zeroLineColor    color.RGBA
 	selectedBarColor color.RGBA
+	boxColor         color.RGBA
 
 	// Mouse in view-space (aka world-space)
 	WVx, WVy int32
@@ -78,6 +95,12 @@ type BaseGraph struct {
 
 	selected bool
 
+	// Fixed view, nil means follow the model.
+	view *View
+
+	// Lanes shown by the synapse graphs in the multi-lane modes.
+	lanes LaneSelection
+
 	activeLane *samples.SamplesLane
 }
 
@@ -92,11 +115,16 @@ func (bg *BaseGraph) Initialize(rect sdl.Rect, dc *gg.Context) {
 	bg.zeroLineColor = color.RGBA{127, 127, 255, 127}
 	// bg.zeroLineColor = color.RGBA{255, 255, 255, 255}
 	bg.selectedBarColor = color.RGBA{127, 127, 255, 255}
+	bg.boxColor = color.RGBA{127, 127, 255, 64}
 
 	bg.borderOffsetX = 4.0
 	bg.borderOffsetY = 4.0
 
 	bg.pixels = dc.Image().(*image.RGBA)
+	bg.Bounds = rect
+
+	// One of each color.
+	bg.lanes = LaneSelection{Start: 0, End: len(laneColors) - 1}
 
 	bg.upperX = float64(rect.W) - bg.borderOffsetX*2.0
 	bg.upperY = float64(rect.H) - bg.borderOffsetY*2.0
@@ -111,6 +139,61 @@ func (bg *BaseGraph) Name() string {
 	return bg.name
 }
 
+func (bg *BaseGraph) SetView(view *View) {
+	bg.view = view
+}
+
+func (bg *BaseGraph) Image() *image.RGBA {
+	return bg.pixels
+}
+
+// TimeBased graphs show samples over the range. They share the crosshair
+// and the Navigator's zoom and pan.
+func (bg *BaseGraph) TimeBased() bool {
+	return true
+}
+
+// TimeAt maps a window x, over a graph positioned at px, to sample time.
+func (bg *BaseGraph) TimeAt(vx, px int32) float64 {
+	ux := bg.Linear(bg.borderOffsetX, float64(bg.Bounds.W)-bg.borderOffsetX, float64(vx-px))
+	return bg.Lerp(float64(bg.scanStart), float64(bg.scanEnd), ux)
+}
+
+// ScanWindow is the range of samples drawn.
+func (bg *BaseGraph) ScanWindow() (start, end int) {
+	return bg.scanStart, bg.scanEnd
+}
+
+func (bg *BaseGraph) Size() (w, h int32) {
+	return bg.Bounds.W, bg.Bounds.H
+}
+
+// scanRange is the view's range, the model's if RangeSync is enabled,
+// otherwise the samples' own. While a run is live it stops at the head.
+func (bg *BaseGraph) scanRange(lanes *samples.Samples) (start, end int) {
+	if bg.view != nil {
+		return bg.view.Start, bg.view.End
+	}
+
+	sync := deuron.SimModel.GetFloat("RangeSync")
+	if sync == 1 {
+		start, end = int(deuron.SimModel.GetFloat("Range_Start")), int(deuron.SimModel.GetFloat("Range_End"))
+	} else {
+		start, end = lanes.GetRange()
+	}
+
+	return liveRange(start, end)
+}
+
+// activeSynapse is the lane shown by graphs of a single synapse.
+func (bg *BaseGraph) activeSynapse() int {
+	if bg.view != nil {
+		return bg.view.Lane
+	}
+
+	return int(deuron.SimModel.GetFloat("Active_Synapse"))
+}
+
 func (bg *BaseGraph) SetGraphics(renderer *sdl.Renderer, texture *sdl.Texture) {
 	bg.renderer = renderer
 	bg.texture = texture
@@ -146,13 +229,39 @@ func (bg *BaseGraph) Draw(rect sdl.Rect, dc *gg.Context) {
 }
 
 func (bg *BaseGraph) postDraw(rect sdl.Rect) {
+	// Offscreen graphs have nowhere to blit to.
+	if bg.texture == nil {
+		return
+	}
+
+	// Only the part inside the viewport is shown, the texture is updated
+	// from the first pixel in view.
+	clip := rect
+	if viewport.W > 0 {
+		var inside bool
+		clip, inside = rect.Intersect(&viewport)
+		if !inside {
+			return
+		}
+	}
+	offset := int(clip.Y-rect.Y)*bg.pixels.Stride + int(clip.X-rect.X)*4
+
 	// -------------------------------------------
 	// Blit pixels
 	// -------------------------------------------
-	bg.texture.Update(&rect, bg.pixels.Pix, bg.pixels.Stride)
+	bg.texture.Update(&clip, bg.pixels.Pix[offset:], bg.pixels.Stride)
 
 	// Now copy the texture onto the target (aka the display)
-	bg.renderer.Copy(bg.texture, &rect, &rect)
+	bg.renderer.Copy(bg.texture, &clip, &clip)
+}
+
+// Where the graphs are shown, graphs scrolled past it are cut off. Empty
+// shows them whole.
+var viewport sdl.Rect
+
+// SetViewport sets the part of the window the graphs are shown in.
+func SetViewport(rect sdl.Rect) {
+	viewport = rect
 }
 
 func (bg *BaseGraph) drawSelectBar(height int32, dc *gg.Context) {
@@ -185,16 +294,30 @@ func (bg *BaseGraph) drawZeroLine(rect sdl.Rect, dc *gg.Context) {
 }
 
 func (bg *BaseGraph) drawVerticalTimeBar(rect sdl.Rect, dc *gg.Context) float64 {
+	// There isn't a mouse over a fixed view.
+	if bg.view != nil {
+		return 0
+	}
+
 	dc.Identity()
 	dc.Translate(bg.borderOffsetX, bg.borderOffsetY)
 	dc.SetLineWidth(1.0)
 
+	// The Navigator's box-select.
+	if boxStart >= 0 {
+		x0 := bg.Lerp(0, bg.upperX, bg.Linear(float64(bg.scanStart), float64(bg.scanEnd), boxStart))
+		x1 := bg.Lerp(0, bg.upperX, bg.Linear(float64(bg.scanStart), float64(bg.scanEnd), boxEnd))
+		dc.SetColor(bg.boxColor)
+		dc.DrawRectangle(math.Min(x0, x1), 0, math.Abs(x1-x0), float64(rect.H)-bg.borderOffsetY*2)
+		dc.Fill()
+	}
+
 	// we need to map "backwards" in order to position the vertical bar
 	// withing the scan window. As usual we map to unit-space so we can map
 	// to whatever destination space needed, in this case we map back to
 	// window-space. We also need to truncate from float to int so the bar
 	// jumps from "t" to "t".
-	uspX := bg.Linear(float64(bg.scanStart), float64(bg.scanEnd), float64(int(bg.GVx)))
+	uspX := bg.Linear(float64(bg.scanStart), float64(bg.scanEnd), float64(int(bg.barTime())))
 
 	// Map from unit-space to window-space
 	winX := bg.Lerp(0, bg.upperX, uspX)
@@ -207,6 +330,15 @@ func (bg *BaseGraph) drawVerticalTimeBar(rect sdl.Rect, dc *gg.Context) float64
 	return winX
 }
 
+// barTime is the crosshair's time, shared by every graph, or the mouse's
+// over this graph.
+func (bg *BaseGraph) barTime() float64 {
+	if crosshair >= 0 {
+		return crosshair
+	}
+	return bg.GVx
+}
+
 func (bg *BaseGraph) drawTitle(rect sdl.Rect, dc *gg.Context) {
 	dc.Identity()
 	dc.Translate(bg.borderOffsetX, bg.borderOffsetY)
@@ -227,12 +359,25 @@ func (bg *BaseGraph) drawMinMax(min, max float64, rect sdl.Rect, dc *gg.Context)
 }
 
 func (bg *BaseGraph) drawMouseInfo(winX float64, rect sdl.Rect, dc *gg.Context) {
+	if bg.view != nil {
+		return
+	}
+
 	dc.Identity()
 	dc.Translate(bg.borderOffsetX, bg.borderOffsetY)
 	dc.SetColor(bg.mouseTextColor)
 
 	upperYInv := float64(rect.H) - bg.upperY + bg.borderOffsetY
-	dc.DrawString(fmt.Sprintf("(%d, %0.3f)", int(bg.GVx), bg.GVy), winX+1, upperYInv)
+
+	// The crosshair reads the lane's value at its time.
+	t := int(bg.barTime())
+	value := bg.GVy
+	if crosshair >= 0 && bg.activeLane != nil && t >= 0 && t < len(bg.activeLane.Values) {
+		if v, ok := bg.activeLane.Values[t].Value.(float64); ok {
+			value = v
+		}
+	}
+	dc.DrawString(fmt.Sprintf("(%d, %0.3f)", t, value), winX+1, upperYInv)
 
 	// g.DC.DrawString(fmt.Sprintf("L(%0.2f, %0.2f)", g.GVx, g.GVy), 5, upperYInv)
 	// g.DC.DrawString(fmt.Sprintf("%d", int(g.GVx)), winX+1, upperYInv)
@@ -0,0 +1,217 @@
+package graphs
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/fogleman/gg"
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/wdevore/Deuron5/deuron"
+	"github.com/wdevore/Deuron5/deuron/app/comm"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+// Lane modes of the synapse graphs (PSP, surge and weight) set by the
+// model's Lane_Mode.
+const (
+	// Only the Active_Synapse.
+	LaneSingle = 0
+	// Several lanes drawn over each other.
+	LaneOverlay = 1
+	// Several lanes each in a strip of its own.
+	LaneMultiples = 2
+
+	LaneModes = 3
+)
+
+// Lane colors, in turn, for the overlays and small multiples.
+var laneColors = []color.RGBA{
+	{255, 127, 0, 255},
+	{127, 255, 255, 255},
+	{255, 255, 127, 255},
+	{127, 255, 127, 255},
+	{255, 127, 255, 255},
+	{127, 160, 255, 255},
+	{255, 80, 80, 255},
+	{200, 200, 200, 255},
+}
+
+// laneColor is the color of lane i of n. Past the fixed colors they're
+// evenly spaced hues so that every lane is distinct.
+func laneColor(i, n int) color.RGBA {
+	if n <= len(laneColors) {
+		return laneColors[i]
+	}
+
+	h := float64(i) / float64(n) * 6.0
+	x := 1.0 - math.Abs(math.Mod(h, 2.0)-1.0)
+
+	r, g, b := 0.0, 0.0, 0.0
+	switch int(h) {
+	case 0:
+		r, g = 1, x
+	case 1:
+		r, g = x, 1
+	case 2:
+		g, b = 1, x
+	case 3:
+		g, b = x, 1
+	case 4:
+		r, b = x, 1
+	default:
+		r, b = 1, x
+	}
+
+	// Lightened to show up on the dark background.
+	c := func(v float64) uint8 { return uint8(127 + 128*v) }
+	return color.RGBA{c(r), c(g), c(b), 255}
+}
+
+// LaneSelection is the lanes, Start to End inclusive, a synapse graph shows
+// when it isn't in LaneSingle mode. While the graph is selected the panel's
+// Lane_Start and Lane_End edit it rather than the graph's range.
+type LaneSelection struct {
+	Start int
+	End   int
+}
+
+// laneMode is the model's Lane_Mode. Exports, with a fixed view, only
+// show the view's lane.
+func (bg *BaseGraph) laneMode() int {
+	if bg.view != nil {
+		return LaneSingle
+	}
+
+	return int(deuron.SimModel.GetFloat("Lane_Mode"))
+}
+
+// selectedLanes returns the lanes of the graph's selection, clamped to the
+// lanes there are.
+func (bg *BaseGraph) selectedLanes(sams *samples.Samples) []*samples.SamplesLane {
+	lanes := []*samples.SamplesLane{}
+
+	last := sams.GetLanes().Size() - 1
+	start := int(math.Max(0, float64(bg.lanes.Start)))
+	end := int(math.Min(float64(last), float64(bg.lanes.End)))
+
+	for id := start; id <= end; id++ {
+		lane, ok := sams.GetLanes().Get(id)
+		if ok {
+			lanes = append(lanes, lane.(*samples.SamplesLane))
+		}
+	}
+
+	return lanes
+}
+
+// sendLaneSelection shows the graph's lanes in the panel's fields.
+func (bg *BaseGraph) sendLaneSelection(source string) {
+	comm.MsgBus.Send3(source, "Model", "Set", "", "", "Lane_Start", fmt.Sprintf("%d", bg.lanes.Start))
+	comm.MsgBus.Send3(source, "Model", "Set", "", "", "Lane_End", fmt.Sprintf("%d", bg.lanes.End))
+}
+
+// handleLaneRange changes the lane selection from the panel's fields.
+func (bg *BaseGraph) handleLaneRange(msg *comm.MessageEvent) bool {
+	if msg.Target != "Data" || msg.Action != "Changed" {
+		return false
+	}
+
+	switch msg.Field {
+	case "Lane_Start", "Lane_End":
+		value, err := strconv.ParseFloat(msg.Value, 64)
+		if err != nil {
+			fmt.Printf("Error %s: %v\n", msg.Field, err)
+			return true
+		}
+		if msg.Field == "Lane_Start" {
+			bg.lanes.Start = int(value)
+		} else {
+			bg.lanes.End = int(value)
+		}
+		return true
+	}
+
+	return false
+}
+
+// drawLanes draws the lanes over each other or, for LaneMultiples, each in
+// a strip of its own. Each lane is labelled with its synapse id in its
+// color.
+func (bg *BaseGraph) drawLanes(lanes []*samples.SamplesLane, mode int, rect sdl.Rect, dc *gg.Context) {
+	if len(lanes) == 0 {
+		return
+	}
+
+	// Overlays share a scale.
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, lane := range lanes {
+		min = math.Min(min, lane.Min)
+		max = math.Max(max, lane.Max)
+	}
+
+	stripH := bg.upperY
+	if mode == LaneMultiples {
+		stripH = bg.upperY / float64(len(lanes))
+	}
+
+	for i, lane := range lanes {
+		bottom := 0.0
+		lmin, lmax := min, max
+		if mode == LaneMultiples {
+			// The first lane is at the top.
+			bottom = bg.upperY - float64(i+1)*stripH
+			lmin, lmax = lane.Min, lane.Max
+
+			dc.SetColor(bg.borderColor)
+			dc.MoveTo(0, bottom)
+			dc.LineTo(bg.upperX, bottom)
+			dc.Stroke()
+		}
+
+		dc.SetColor(laneColor(i, len(lanes)))
+		first := true
+		for t := bg.scanStart; t < bg.scanEnd && t < len(lane.Values); t++ {
+			sample := lane.Values[t]
+			if sample.Value == nil {
+				continue
+			}
+
+			uspX := bg.Linear(float64(bg.scanStart), float64(bg.scanEnd), sample.Time)
+			uspY := 0.5
+			if lmax > lmin {
+				uspY = (sample.Value.(float64) - lmin) / (lmax - lmin)
+			}
+			winX := bg.Lerp(0, bg.upperX, uspX)
+			winY := bottom + bg.Lerp(0, stripH, uspY)
+
+			if first {
+				dc.MoveTo(winX, winY)
+				first = false
+			} else {
+				dc.LineTo(winX, winY)
+			}
+		}
+		dc.Stroke()
+	}
+
+	// Labels are drawn the right way up.
+	dc.Identity()
+	dc.Translate(bg.borderOffsetX, bg.borderOffsetY)
+	top := float64(rect.H) - bg.upperY + bg.borderOffsetY
+
+	if mode == LaneOverlay {
+		dc.SetColor(bg.maxTextColor)
+		dc.DrawString(fmt.Sprintf("[%0.3f, %0.3f]", min, max), 5, top+15)
+	}
+
+	for i, lane := range lanes {
+		dc.SetColor(laneColor(i, len(lanes)))
+		if mode == LaneMultiples {
+			dc.DrawString(fmt.Sprintf("%d [%0.3f, %0.3f]", lane.Id, lane.Min, lane.Max), 5, top+float64(i)*stripH+12)
+		} else {
+			dc.DrawString(fmt.Sprintf("%d", lane.Id), 5+float64(i)*30, top)
+		}
+	}
+}
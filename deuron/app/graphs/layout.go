@@ -0,0 +1,95 @@
+package graphs
+
+import (
+	"fmt"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// GraphSpec describes a graph of the app's stack, for example:
+//
+//	{"Type": "psp", "Height": 200}
+//	{"Type": "scatter", "Title": "dw vs dt", "X": "dt", "Y": "dw", "Width": 300, "Height": 150, "SameRow": true}
+//	{"Type": "trace", "Samples": "neurondt", "Height": 50, "Hidden": true}
+//
+// Type is one of the export names, "scatter" or "trace".
+type GraphSpec struct {
+	Type string
+
+	// Defaults to the graph's usual title.
+	Title string
+
+	// Pixels, Width defaults to the window's width.
+	Width  int
+	Height int
+
+	// Placed to the right of the graph before rather than below it.
+	SameRow bool
+
+	// Hidden graphs aren't created.
+	Hidden bool
+
+	// Series of a scatter graph's axes and a trace graph.
+	X, Y    string
+	Samples string
+}
+
+// Validate checks the graph can be created.
+func (gs *GraphSpec) Validate() error {
+	switch gs.Type {
+	case "scatter":
+		if _, ok := seriesByName[gs.X]; !ok {
+			return fmt.Errorf("scatter graph X (%s) isn't one of %v", gs.X, SeriesNames)
+		}
+		if _, ok := seriesByName[gs.Y]; !ok {
+			return fmt.Errorf("scatter graph Y (%s) isn't one of %v", gs.Y, SeriesNames)
+		}
+	case "trace":
+		if _, ok := seriesByName[gs.Samples]; !ok {
+			return fmt.Errorf("trace graph Samples (%s) isn't one of %v", gs.Samples, SeriesNames)
+		}
+	default:
+		if _, ok := exportables[gs.Type]; !ok {
+			return fmt.Errorf("unknown graph Type (%s)", gs.Type)
+		}
+	}
+
+	if gs.Width < 0 || gs.Height <= 0 {
+		return fmt.Errorf("graph (%s) needs a Height > 0", gs.Type)
+	}
+
+	return nil
+}
+
+// Create makes the graph, width is used when the spec doesn't have one.
+func (gs *GraphSpec) Create(renderer *sdl.Renderer, texture *sdl.Texture, width int) IGraph {
+	if gs.Width > 0 {
+		width = gs.Width
+	}
+
+	var graph IGraph
+	title := gs.Title
+
+	switch gs.Type {
+	case "scatter":
+		graph = NewScatterGraph(renderer, texture, width, gs.Height, gs.X, gs.Y)
+		if title == "" {
+			title = fmt.Sprintf("%s vs %s", seriesByName[gs.Y].title, seriesByName[gs.X].title)
+		}
+	case "trace":
+		graph = NewTraceGraph(renderer, texture, width, gs.Height, gs.Samples)
+		if title == "" {
+			title = seriesByName[gs.Samples].title
+		}
+	default:
+		e := exportables[gs.Type]
+		graph = e.create(renderer, texture, width, gs.Height)
+		if title == "" {
+			title = e.title
+		}
+	}
+
+	graph.SetName(title)
+
+	return graph
+}
@@ -0,0 +1,60 @@
+package graphs
+
+import (
+	"sync"
+
+	"github.com/wdevore/Deuron5/deuron"
+)
+
+// The time of the newest sample of a live run copied to samples.Sim, -1
+// when nothing is running. Samples past it haven't been copied yet, or are
+// from the previous run, so the graphs stop there.
+var (
+	head     = -1.0
+	headLock sync.Mutex
+)
+
+// SetHead moves the head of a live run. -1 ends the run.
+func SetHead(t float64) {
+	headLock.Lock()
+	defer headLock.Unlock()
+	head = t
+}
+
+func getHead() float64 {
+	headLock.Lock()
+	defer headLock.Unlock()
+	return head
+}
+
+// Live is true while a run is being drawn.
+func Live() bool {
+	return getHead() >= 0
+}
+
+// liveRange clamps start to end to the head. With Follow on, it is instead
+// the Follow_Window milliseconds up to the head, scrolling along with it.
+func liveRange(start, end int) (int, int) {
+	head := getHead()
+	if head < 0 {
+		return start, end
+	}
+
+	h := int(head) + 1
+
+	if deuron.SimModel.GetFloat("Follow") == 1 {
+		start = h - int(deuron.SimModel.GetFloat("Follow_Window"))
+		if start < 0 {
+			start = 0
+		}
+		return start, h
+	}
+
+	if end > h {
+		end = h
+	}
+	if start > end {
+		start = end
+	}
+	return start, end
+}
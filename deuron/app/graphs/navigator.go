@@ -0,0 +1,236 @@
+package graphs
+
+import (
+	"fmt"
+	"math"
+
+	sll "github.com/emirpasic/gods/lists/singlylinkedlist"
+	"github.com/wdevore/Deuron5/deuron"
+	"github.com/wdevore/Deuron5/deuron/app/comm"
+	"github.com/wdevore/Deuron5/deuron/app/events"
+	"github.com/wdevore/Deuron5/deuron/app/gui"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+const (
+	// Range width each wheel step zooms in to.
+	zoomFactor = 0.8
+
+	// Narrowest range zooming or a box goes to.
+	minRange = 10.0
+
+	// Pixels the mouse moves before a press is a drag rather than a click.
+	dragSlop = 3
+)
+
+// The crosshair and box-select are drawn by every time graph, -1 is off.
+var (
+	crosshair = -1.0
+
+	boxStart = -1.0
+	boxEnd   = -1.0
+)
+
+// ITimeGraph is a graph of samples over time.
+type ITimeGraph interface {
+	IGraph
+	gui.IWidget
+
+	TimeBased() bool
+	TimeAt(vx, px int32) float64
+	ScanWindow() (start, end int)
+	Size() (w, h int32)
+}
+
+// Navigator zooms, pans and box-selects the range of every time graph
+// with the mouse and keeps a crosshair at the hovered time:
+//
+//	wheel         zoom around the cursor
+//	left drag     pan
+//	right drag    select the range
+//	right click   the whole run
+//
+// Changing the range turns on RangeSync so the graphs move together.
+type Navigator struct {
+	graphs *sll.List
+
+	dragging bool
+	boxing   bool
+	moved    bool
+
+	anchorX     int32
+	anchorStart float64
+	anchorEnd   float64
+	anchor      ITimeGraph
+}
+
+func NewNavigator(graphs *sll.List) *Navigator {
+	n := new(Navigator)
+	n.graphs = graphs
+	return n
+}
+
+// Handle returns true if it used the event. Otherwise the event, which a
+// press and release without a drag turns into a click, goes to the graphs.
+func (n *Navigator) Handle(vx, vy int32, eventType events.MouseEventType) (events.MouseEventType, bool) {
+	g := n.graphAt(vx, vy)
+
+	switch eventType {
+	case events.MouseMotion:
+		if n.dragging {
+			n.drag(vx)
+			return eventType, true
+		}
+
+		if n.boxing {
+			px, _ := n.anchor.Position()
+			boxEnd = n.anchor.TimeAt(vx, px)
+			crosshair = boxEnd
+			redraw()
+			return eventType, true
+		}
+
+		n.hover(g, vx)
+		return eventType, false
+	case events.MouseButton:
+		if g == nil {
+			return eventType, false
+		}
+
+		start, end := g.ScanWindow()
+		n.dragging = true
+		n.moved = false
+		n.anchor = g
+		n.anchorX = vx
+		n.anchorStart, n.anchorEnd = float64(start), float64(end)
+		return eventType, true
+	case events.MouseButtonUp:
+		if !n.dragging {
+			return eventType, false
+		}
+
+		n.dragging = false
+		if !n.moved {
+			// It was a click.
+			return events.MouseButton, false
+		}
+		return eventType, true
+	case events.MouseRightButton:
+		if g == nil {
+			return eventType, false
+		}
+
+		px, _ := g.Position()
+		n.boxing = true
+		n.anchor = g
+		n.anchorX = vx
+		boxStart = g.TimeAt(vx, px)
+		boxEnd = boxStart
+		return eventType, true
+	case events.MouseRightButtonUp:
+		if !n.boxing {
+			return eventType, false
+		}
+
+		n.boxing = false
+		start, end := math.Min(boxStart, boxEnd), math.Max(boxStart, boxEnd)
+		boxStart, boxEnd = -1, -1
+
+		if vx-n.anchorX < dragSlop && n.anchorX-vx < dragSlop {
+			setRange(0, deuron.SimModel.GetFloat("Samples"))
+		} else {
+			setRange(start, end)
+		}
+		return eventType, true
+	case events.MouseWheelUp, events.MouseWheelDown:
+		if g == nil {
+			return eventType, false
+		}
+
+		factor := zoomFactor
+		if eventType == events.MouseWheelDown {
+			factor = 1 / zoomFactor
+		}
+
+		// The time under the cursor stays put.
+		px, _ := g.Position()
+		t := g.TimeAt(vx, px)
+		start, end := g.ScanWindow()
+		setRange(t-(t-float64(start))*factor, t+(float64(end)-t)*factor)
+		return eventType, true
+	}
+
+	return eventType, false
+}
+
+func (n *Navigator) hover(g ITimeGraph, vx int32) {
+	t := -1.0
+	if g != nil {
+		px, _ := g.Position()
+		t = g.TimeAt(vx, px)
+	}
+
+	if t != crosshair {
+		crosshair = t
+		redraw()
+	}
+}
+
+func (n *Navigator) drag(vx int32) {
+	dx := vx - n.anchorX
+	if dx > dragSlop || dx < -dragSlop {
+		n.moved = true
+	}
+
+	if !n.moved {
+		return
+	}
+
+	// Dragging right moves earlier samples into view.
+	w, _ := n.anchor.Size()
+	shift := float64(dx) / float64(w) * (n.anchorEnd - n.anchorStart)
+	setRange(n.anchorStart-shift, n.anchorEnd-shift)
+}
+
+// graphAt is the visible time graph under the mouse.
+func (n *Navigator) graphAt(vx, vy int32) ITimeGraph {
+	// Graphs can't check themselves until there are samples.
+	if samples.Sim == nil {
+		return nil
+	}
+
+	it := n.graphs.Iterator()
+	for it.Next() {
+		g, ok := it.Value().(ITimeGraph)
+		if !ok || !g.TimeBased() || !g.Check() {
+			continue
+		}
+
+		px, py := g.Position()
+		w, h := g.Size()
+		if gui.PointInside(vx, vy, px, py, w, h) {
+			return g
+		}
+	}
+
+	return nil
+}
+
+// setRange shows start to end, kept within the run, on every graph.
+func setRange(start, end float64) {
+	duration := deuron.SimModel.GetFloat("Samples")
+
+	width := math.Min(math.Max(end-start, minRange), duration)
+	start = math.Max(0, math.Min(start, duration-width))
+	end = start + width
+
+	if deuron.SimModel.GetFloat("RangeSync") != 1 {
+		comm.MsgBus.Send3("Navigator", "Model", "Set", "", "", "RangeSync", "1")
+	}
+	comm.MsgBus.Send3("Navigator", "Model", "Set", "", "", "Range_Start", fmt.Sprintf("%d", int(math.Round(start))))
+	comm.MsgBus.Send3("Navigator", "Model", "Set", "", "", "Range_End", fmt.Sprintf("%d", int(math.Round(end))))
+}
+
+func redraw() {
+	comm.MsgBus.Send2("Gui", "Data", "Changed", "", "", "")
+}
@@ -197,14 +197,7 @@ func (g *NeuronPspGraph) Check() bool {
 }
 
 func (g *NeuronPspGraph) setScanWindow(lanes *samples.Samples) {
-	// If RangeSync is enabled then we use the Model's range
-	sync := deuron.SimModel.GetFloat("RangeSync")
-	if sync == 1 {
-		g.scanStart = int(deuron.SimModel.GetFloat("Range_Start"))
-		g.scanEnd = int(deuron.SimModel.GetFloat("Range_End"))
-	} else {
-		g.scanStart, g.scanEnd = lanes.GetRange()
-	}
+	g.scanStart, g.scanEnd = g.scanRange(lanes)
 	g.scanIdx = g.scanStart
 }
 
@@ -118,10 +118,13 @@ func (g *PointsGraph) Draw() {
 			px, py, c, more = g.accessor()
 		}
 
-		g.texture.Update(&g.Rect, g.pixels.Pix, g.pixels.Stride)
+		// Offscreen graphs have nowhere to blit to.
+		if g.texture != nil {
+			g.texture.Update(&g.Rect, g.pixels.Pix, g.pixels.Stride)
 
-		// Now copy the texture onto the target (aka the display)
-		g.renderer.Copy(g.texture, &g.Rect, &g.Rect)
+			// Now copy the texture onto the target (aka the display)
+			g.renderer.Copy(g.texture, &g.Rect, &g.Rect)
+		}
 
 		g.MarkDirty(false)
 	}
@@ -130,3 +133,7 @@ func (g *PointsGraph) Draw() {
 func (g *PointsGraph) Check() bool {
 	return false
 }
+
+func (g *PointsGraph) Image() *image.RGBA {
+	return g.pixels
+}
@@ -10,7 +10,6 @@ import (
 	sll "github.com/emirpasic/gods/lists/singlylinkedlist"
 
 	"github.com/veandco/go-sdl2/sdl"
-	"github.com/wdevore/Deuron5/deuron"
 	"github.com/wdevore/Deuron5/deuron/app/comm"
 	"github.com/wdevore/Deuron5/deuron/app/gui"
 	"github.com/wdevore/Deuron5/simulation/samples"
@@ -209,15 +208,7 @@ func (g *PostSpikeGraph) Check() bool {
 }
 
 func (g *PostSpikeGraph) setScanWindow(lanes *samples.Samples) {
-	// If RangeSync is enabled then we use the Model's range
-	sync := deuron.SimModel.GetFloat("RangeSync")
-
-	if sync == 1 {
-		g.scanStart = int(deuron.SimModel.GetFloat("Range_Start"))
-		g.scanEnd = int(deuron.SimModel.GetFloat("Range_End"))
-	} else {
-		g.scanStart, g.scanEnd = lanes.GetRange()
-	}
+	g.scanStart, g.scanEnd = g.scanRange(lanes)
 
 	g.scanIdx = g.scanStart
 }
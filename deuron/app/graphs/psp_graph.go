@@ -8,7 +8,6 @@ import (
 
 	sll "github.com/emirpasic/gods/lists/singlylinkedlist"
 	"github.com/veandco/go-sdl2/sdl"
-	"github.com/wdevore/Deuron5/deuron"
 	"github.com/wdevore/Deuron5/deuron/app/comm"
 	"github.com/wdevore/Deuron5/deuron/app/gui"
 	"github.com/wdevore/Deuron5/simulation/samples"
@@ -53,6 +52,11 @@ func (g *PspGraph) Listen(msg *comm.MessageEvent) {
 		return
 	}
 
+	// Multiple lanes are chosen with the panel's lane fields.
+	if g.laneMode() != LaneSingle && g.handleLaneRange(msg) {
+		return
+	}
+
 	handled = g.handleRange(msg, samples)
 
 	if handled {
@@ -70,13 +74,17 @@ func (g *PspGraph) Handle(vx, vy int32, eventType events.MouseEventType) (handle
 			if g.selected {
 				// Send message to app.go
 				comm.MsgBus.Send2("PspGraph", "Graph", "Selected", "Surge", fmt.Sprintf("%d", g.ID()), "")
-				// Update gui Range fields the sample's range.
-				samples := samples.Sim.PspSamples
-				start, end := samples.GetRange()
-
-				// Send message to panel including the panel's id.
-				comm.MsgBus.Send3("PspGraph", "Model", "Set", "", "", "Lane_Start", fmt.Sprintf("%d", start))
-				comm.MsgBus.Send3("PspGraph", "Model", "Set", "", "", "Lane_End", fmt.Sprintf("%d", end))
+				if g.laneMode() != LaneSingle {
+					g.sendLaneSelection("PspGraph")
+				} else {
+					// Update gui Range fields the sample's range.
+					samples := samples.Sim.PspSamples
+					start, end := samples.GetRange()
+
+					// Send message to panel including the panel's id.
+					comm.MsgBus.Send3("PspGraph", "Model", "Set", "", "", "Lane_Start", fmt.Sprintf("%d", start))
+					comm.MsgBus.Send3("PspGraph", "Model", "Set", "", "", "Lane_End", fmt.Sprintf("%d", end))
+				}
 			} else {
 				comm.MsgBus.Send2("PspGraph", "Graph", "UnSelected", "Surge", fmt.Sprintf("%d", g.ID()), "")
 			}
@@ -108,6 +116,17 @@ func (g *PspGraph) Prep() {
 func (g *PspGraph) Draw() {
 	g.BaseGraph.Draw(g.Rect, g.DC)
 
+	if mode := g.laneMode(); mode != LaneSingle {
+		g.drawLanes(g.selectedLanes(samples.Sim.PspSamples), mode, g.Rect, g.DC)
+
+		g.drawTitle(g.Rect, g.DC)
+		winX := g.drawVerticalTimeBar(g.Rect, g.DC)
+		g.drawMouseInfo(winX, g.Rect, g.DC)
+
+		g.postDraw(g.Rect)
+		return
+	}
+
 	if g.activeLane.Min < 0 {
 		g.drawZeroLine(g.Rect, g.DC)
 	}
@@ -177,7 +196,7 @@ func (g *PspGraph) Check() bool {
 	g.graphIt = lanes.Iterator()
 
 	if g.graphIt.First() {
-		activeSynID := int(deuron.SimModel.GetFloat("Active_Synapse"))
+		activeSynID := g.activeSynapse()
 		lane, _ := lanes.Get(activeSynID)
 		g.activeLane = lane.(*samples.SamplesLane)
 		g.setScanWindow(samples.Sim.PspSamples)
@@ -188,14 +207,7 @@ func (g *PspGraph) Check() bool {
 }
 
 func (g *PspGraph) setScanWindow(lanes *samples.Samples) {
-	// If RangeSync is enabled then we use the Model's range
-	sync := deuron.SimModel.GetFloat("RangeSync")
-	if sync == 1 {
-		g.scanStart = int(deuron.SimModel.GetFloat("Range_Start"))
-		g.scanEnd = int(deuron.SimModel.GetFloat("Range_End"))
-	} else {
-		g.scanStart, g.scanEnd = lanes.GetRange()
-	}
+	g.scanStart, g.scanEnd = g.scanRange(lanes)
 	g.scanIdx = g.scanStart
 }
 
@@ -0,0 +1,116 @@
+package graphs
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/wdevore/Deuron5/deuron/app/comm"
+	"github.com/wdevore/Deuron5/deuron/app/events"
+	"github.com/wdevore/Deuron5/deuron/app/gui"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+const (
+	// PSTH bins are milliseconds wide.
+	psthBin = 5.0
+
+	// Window used when the patterns aren't repeated within a run.
+	psthWindow = 100.0
+)
+
+// PSTHGraph is the post spike rate after a pattern onset, averaged over
+// every onset of every run.
+type PSTHGraph struct {
+	BaseGraph
+	gui.BaseWidget
+
+	barColor color.RGBA
+
+	window float64
+	rates  []float64
+	trials int
+}
+
+func NewPSTHGraph(renderer *sdl.Renderer, texture *sdl.Texture, width, height int) IGraph {
+	g := new(PSTHGraph)
+	g.BaseWidget.Initialize(g, width, height)
+	g.BaseGraph.Initialize(g.Rect, g.DC)
+	g.SetGraphics(renderer, texture)
+
+	g.barColor = color.RGBA{127, 255, 255, 255}
+
+	return g
+}
+
+func (g *PSTHGraph) Listen(msg *comm.MessageEvent) {
+}
+
+func (g *PSTHGraph) Handle(vx, vy int32, eventType events.MouseEventType) (handled bool, id int) {
+	return false, -1
+}
+
+// TimeBased is false, the graph isn't of the range.
+func (g *PSTHGraph) TimeBased() bool {
+	return false
+}
+
+func (g *PSTHGraph) SetSeries(accessor SeriesAccessor) {
+}
+
+// Destroy release resources
+func (g *PSTHGraph) Destroy() {
+}
+
+// Draw renders graph to texture
+func (g *PSTHGraph) Draw() {
+	g.BaseGraph.Draw(g.Rect, g.DC)
+
+	max := 0.0
+	for _, r := range g.rates {
+		if r > max {
+			max = r
+		}
+	}
+	if max == 0 {
+		max = 1
+	}
+
+	barW := g.upperX / float64(len(g.rates))
+
+	g.DC.SetColor(g.barColor)
+	for i, r := range g.rates {
+		h := g.Lerp(0, g.upperY*0.9, r/max)
+		g.DC.DrawRectangle(float64(i)*barW+1, 0, barW-2, h)
+		g.DC.Fill()
+	}
+
+	// -------------------------------------------
+	// Draw labels
+	// -------------------------------------------
+	g.drawTitle(g.Rect, g.DC)
+
+	g.DC.Identity()
+	g.DC.Translate(g.borderOffsetX, g.borderOffsetY)
+	g.DC.SetColor(g.maxTextColor)
+	g.DC.DrawString(fmt.Sprintf("onsets: %d, %0.0f ms bins over %0.0f ms, max %0.1f Hz",
+		g.trials, psthBin, g.window, max), 5, float64(g.Rect.H)-g.upperY+g.borderOffsetY)
+
+	g.postDraw(g.Rect)
+}
+
+func (g *PSTHGraph) Check() bool {
+	if len(samples.Runs.Runs()) == 0 {
+		return false
+	}
+
+	// Up to the next onset.
+	g.window = samples.Runs.Interval()
+	if g.window < psthBin {
+		g.window = psthWindow
+	}
+
+	g.rates, g.trials = samples.Runs.PSTH(g.window, psthBin)
+
+	return g.trials > 0
+}
@@ -0,0 +1,154 @@
+package graphs
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/wdevore/Deuron5/deuron/app/comm"
+	"github.com/wdevore/Deuron5/deuron/app/events"
+	"github.com/wdevore/Deuron5/deuron/app/gui"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+// RasterGraph stacks the post spikes of each run, the oldest at the top,
+// over the same range as the Post Spike graph.
+type RasterGraph struct {
+	BaseGraph
+	gui.BaseWidget
+
+	spikeColor color.RGBA
+	onsetColor color.RGBA
+}
+
+func NewRasterGraph(renderer *sdl.Renderer, texture *sdl.Texture, width, height int) IGraph {
+	g := new(RasterGraph)
+	g.BaseWidget.Initialize(g, width, height)
+	g.BaseGraph.Initialize(g.Rect, g.DC)
+	g.SetGraphics(renderer, texture)
+
+	g.spikeColor = color.RGBA{127, 255, 255, 255}
+	g.onsetColor = color.RGBA{255, 127, 0, 160}
+
+	return g
+}
+
+func (g *RasterGraph) Listen(msg *comm.MessageEvent) {
+	if !g.selected {
+		return
+	}
+
+	samples := samples.Sim.CellSamples
+
+	px, py := g.Position()
+	handled := g.handleScroll(msg, samples, px, py, g.Rect)
+
+	if handled {
+		return
+	}
+
+	g.handleRange(msg, samples)
+}
+
+func (g *RasterGraph) Handle(vx, vy int32, eventType events.MouseEventType) (handled bool, id int) {
+	inside := gui.PointInside(vx, vy, g.Rect.X, g.Rect.Y, g.Rect.W, g.Rect.H)
+
+	switch eventType {
+	case events.MouseButton:
+		if inside {
+			g.selected = !g.selected
+			if g.selected {
+				// Send message to app.go
+				comm.MsgBus.Send2("RasterGraph", "Graph", "Selected", "Raster", fmt.Sprintf("%d", g.ID()), "")
+				samples := samples.Sim.CellSamples
+				start, end := samples.GetRange()
+
+				comm.MsgBus.Send3("RasterGraph", "Model", "Set", "", "", "Lane_Start", fmt.Sprintf("%d", start))
+				comm.MsgBus.Send3("RasterGraph", "Model", "Set", "", "", "Lane_End", fmt.Sprintf("%d", end))
+			} else {
+				comm.MsgBus.Send2("RasterGraph", "Graph", "UnSelected", "Raster", fmt.Sprintf("%d", g.ID()), "")
+			}
+			return true, g.ID()
+		}
+		break
+	case events.MouseMotion:
+		handled = g.handleMotion(vx, vy, g.BaseWidget, inside)
+		if handled {
+			return true, g.ID()
+		}
+		break
+	}
+
+	return false, -1
+}
+
+func (g *RasterGraph) SetSeries(accessor SeriesAccessor) {
+}
+
+// Destroy release resources
+func (g *RasterGraph) Destroy() {
+}
+
+// Draw renders graph to texture
+func (g *RasterGraph) Draw() {
+	g.BaseGraph.Draw(g.Rect, g.DC)
+
+	runs := samples.Runs.Runs()
+
+	// A row per run, the newest at the bottom.
+	rowH := g.upperY / float64(len(runs))
+
+	mapX := func(t float64) (float64, bool) {
+		if t < float64(g.scanStart) || t >= float64(g.scanEnd) {
+			return 0, false
+		}
+		return g.Lerp(0, g.upperX, g.Linear(float64(g.scanStart), float64(g.scanEnd), t)), true
+	}
+
+	for i, run := range runs {
+		top := g.upperY - float64(i)*rowH
+		bottom := top - rowH
+
+		g.DC.SetColor(g.onsetColor)
+		for _, t := range run.Onsets {
+			if winX, ok := mapX(t); ok {
+				g.DC.MoveTo(winX, bottom)
+				g.DC.LineTo(winX, top)
+				g.DC.Stroke()
+			}
+		}
+
+		g.DC.SetColor(g.spikeColor)
+		for _, t := range run.Spikes {
+			if winX, ok := mapX(t); ok {
+				g.DC.MoveTo(winX, bottom+rowH*0.1)
+				g.DC.LineTo(winX, top-rowH*0.1)
+				g.DC.Stroke()
+			}
+		}
+	}
+
+	// -------------------------------------------
+	// Draw labels and ruler marks
+	// -------------------------------------------
+	g.drawTitle(g.Rect, g.DC)
+
+	g.DC.Identity()
+	g.DC.Translate(g.borderOffsetX, g.borderOffsetY)
+	g.DC.SetColor(g.maxTextColor)
+	g.DC.DrawString(fmt.Sprintf("runs: %d", len(runs)), 5, float64(g.Rect.H)-g.upperY+g.borderOffsetY)
+
+	g.drawVerticalTimeBar(g.Rect, g.DC)
+
+	g.postDraw(g.Rect)
+}
+
+func (g *RasterGraph) Check() bool {
+	if samples.Sim == nil || len(samples.Runs.Runs()) == 0 {
+		return false
+	}
+
+	g.scanStart, g.scanEnd = g.scanRange(samples.Sim.CellSamples)
+
+	return true
+}
@@ -0,0 +1,219 @@
+package graphs
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/wdevore/Deuron5/deuron/app/comm"
+	"github.com/wdevore/Deuron5/deuron/app/events"
+	"github.com/wdevore/Deuron5/deuron/app/gui"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+// Side, in pixels, of a scatter point.
+const scatterPoint = 2.0
+
+// ScatterGraph plots one series against another, a point per step of the
+// range, for example, dw against dt for the active synapse. While it's
+// selected the X and Y keys change its axes.
+type ScatterGraph struct {
+	BaseGraph
+	gui.BaseWidget
+
+	pointColor color.RGBA
+
+	x, y string
+
+	xs, ys     []float64
+	minX, maxX float64
+	minY, maxY float64
+}
+
+func NewScatterGraph(renderer *sdl.Renderer, texture *sdl.Texture, width, height int, x, y string) IGraph {
+	g := new(ScatterGraph)
+	g.BaseWidget.Initialize(g, width, height)
+	g.BaseGraph.Initialize(g.Rect, g.DC)
+	g.SetGraphics(renderer, texture)
+
+	g.pointColor = color.RGBA{255, 127, 0, 255}
+
+	g.x, g.y = SeriesNames[0], SeriesNames[1]
+	g.SetAxes(x, y)
+
+	return g
+}
+
+// SetAxes sets the series of each axis. Unknown names are ignored.
+func (g *ScatterGraph) SetAxes(x, y string) {
+	if _, ok := seriesByName[x]; ok {
+		g.x = x
+	} else {
+		fmt.Printf("Unknown scatter series (%s)\n", x)
+	}
+
+	if _, ok := seriesByName[y]; ok {
+		g.y = y
+	} else {
+		fmt.Printf("Unknown scatter series (%s)\n", y)
+	}
+}
+
+// Axes are the names of the x and y series.
+func (g *ScatterGraph) Axes() (x, y string) {
+	return g.x, g.y
+}
+
+func (g *ScatterGraph) Listen(msg *comm.MessageEvent) {
+	if !g.selected {
+		return
+	}
+
+	if msg.Target != "Key" || msg.Message != "CycleAxis" {
+		return
+	}
+
+	switch msg.Action {
+	case "X":
+		g.x = nextSeries(g.x)
+	case "Y":
+		g.y = nextSeries(g.y)
+	default:
+		return
+	}
+
+	redraw()
+}
+
+// nextSeries is the series after name, wrapping around.
+func nextSeries(name string) string {
+	for i, s := range SeriesNames {
+		if s == name {
+			return SeriesNames[(i+1)%len(SeriesNames)]
+		}
+	}
+
+	return SeriesNames[0]
+}
+
+func (g *ScatterGraph) Handle(vx, vy int32, eventType events.MouseEventType) (handled bool, id int) {
+	inside := gui.PointInside(vx, vy, g.Rect.X, g.Rect.Y, g.Rect.W, g.Rect.H)
+
+	switch eventType {
+	case events.MouseButton:
+		if inside {
+			g.selected = !g.selected
+			if g.selected {
+				// Send message to app.go
+				comm.MsgBus.Send2("ScatterGraph", "Graph", "Selected", "Scatter", fmt.Sprintf("%d", g.ID()), "")
+			} else {
+				comm.MsgBus.Send2("ScatterGraph", "Graph", "UnSelected", "Scatter", fmt.Sprintf("%d", g.ID()), "")
+			}
+			return true, g.ID()
+		}
+		break
+	}
+
+	return false, -1
+}
+
+// TimeBased is false, the graph's x axis isn't time.
+func (g *ScatterGraph) TimeBased() bool {
+	return false
+}
+
+func (g *ScatterGraph) SetSeries(accessor SeriesAccessor) {
+}
+
+// Destroy release resources
+func (g *ScatterGraph) Destroy() {
+}
+
+// Draw renders graph to texture
+func (g *ScatterGraph) Draw() {
+	g.BaseGraph.Draw(g.Rect, g.DC)
+
+	// Zero lines, where they're in view.
+	g.DC.SetColor(g.zeroLineColor)
+	if g.minX < 0 && g.maxX > 0 {
+		x := g.Lerp(0, g.upperX, unit(g.minX, g.maxX, 0))
+		g.DC.MoveTo(x, 0)
+		g.DC.LineTo(x, g.upperY)
+		g.DC.Stroke()
+	}
+	if g.minY < 0 && g.maxY > 0 {
+		y := g.Lerp(0, g.upperY, unit(g.minY, g.maxY, 0))
+		g.DC.MoveTo(0, y)
+		g.DC.LineTo(g.upperX, y)
+		g.DC.Stroke()
+	}
+
+	g.DC.SetColor(g.pointColor)
+	for i := range g.xs {
+		winX := g.Lerp(0, g.upperX-scatterPoint, unit(g.minX, g.maxX, g.xs[i]))
+		winY := g.Lerp(0, g.upperY-scatterPoint, unit(g.minY, g.maxY, g.ys[i]))
+		g.DC.DrawRectangle(winX, winY, scatterPoint, scatterPoint)
+	}
+	g.DC.Fill()
+
+	// -------------------------------------------
+	// Draw labels
+	// -------------------------------------------
+	g.drawTitle(g.Rect, g.DC)
+
+	g.DC.Identity()
+	g.DC.Translate(g.borderOffsetX, g.borderOffsetY)
+	top := float64(g.Rect.H) - g.upperY + g.borderOffsetY
+	g.DC.SetColor(g.maxTextColor)
+	g.DC.DrawString(fmt.Sprintf("x: %s [%0.3f, %0.3f]", seriesByName[g.x].title, g.minX, g.maxX), 5, top)
+	g.DC.DrawString(fmt.Sprintf("y: %s [%0.3f, %0.3f]", seriesByName[g.y].title, g.minY, g.maxY), 5, top+15)
+	g.DC.DrawString(fmt.Sprintf("synapse: %d, points: %d, t: [%d, %d)", g.activeSynapse(), len(g.xs), g.scanStart, g.scanEnd), 5, top+30)
+
+	g.postDraw(g.Rect)
+}
+
+func (g *ScatterGraph) Check() bool {
+	sc := samples.Sim
+	if sc == nil {
+		return false
+	}
+
+	// Every sample set is the length of the run.
+	g.scanStart, g.scanEnd = g.scanRange(sc.CellSamples)
+
+	xSeries := seriesByName[g.x]
+	ySeries := seriesByName[g.y]
+	syn := g.activeSynapse()
+
+	g.xs, g.ys = g.xs[:0], g.ys[:0]
+	g.minX, g.maxX = math.Inf(1), math.Inf(-1)
+	g.minY, g.maxY = math.Inf(1), math.Inf(-1)
+
+	for t := g.scanStart; t < g.scanEnd; t++ {
+		x, ok := xSeries.value(sc, syn, t)
+		if !ok {
+			continue
+		}
+		y, ok := ySeries.value(sc, syn, t)
+		if !ok {
+			continue
+		}
+
+		g.xs = append(g.xs, x)
+		g.ys = append(g.ys, y)
+		g.minX, g.maxX = math.Min(g.minX, x), math.Max(g.maxX, x)
+		g.minY, g.maxY = math.Min(g.minY, y), math.Max(g.maxY, y)
+	}
+
+	return len(g.xs) > 0
+}
+
+// unit maps min -> max to 0 -> 1, a single value is in the middle.
+func unit(min, max, value float64) float64 {
+	if max <= min {
+		return 0.5
+	}
+
+	return (value - min) / (max - min)
+}
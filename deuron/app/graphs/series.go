@@ -0,0 +1,94 @@
+package graphs
+
+import (
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+// series is a sample set, or a value derived from them, that the trace
+// and scatter graphs can show by name.
+type series struct {
+	title string
+
+	// sams is the sample set, nil for derived series.
+	sams func(sc *samples.SamplesCollection) *samples.Samples
+
+	// Neuron sample sets only have a lane 0.
+	neuron bool
+
+	derive func(sc *samples.SamplesCollection, syn, t int) (float64, bool)
+}
+
+// SeriesNames are the names of the series, in the order the scatter
+// graph's X and Y keys cycle through them.
+var SeriesNames = []string{"dt", "dw", "weight", "psp", "surge", "neuronpsp", "apfast", "apslow", "neurondt"}
+
+var seriesByName = map[string]series{
+	"dt": {title: "dt", sams: func(sc *samples.SamplesCollection) *samples.Samples { return sc.DtSamples }},
+	// Only the steps where the weight changed, otherwise the unchanged
+	// steps bury the rest at 0.
+	"dw": {title: "dw", derive: func(sc *samples.SamplesCollection, syn, t int) (float64, bool) {
+		if t < 1 {
+			return 0, false
+		}
+		w, ok := laneValue(sc.WeightSamples, syn, t)
+		pw, pok := laneValue(sc.WeightSamples, syn, t-1)
+		if !ok || !pok || w == pw {
+			return 0, false
+		}
+		return w - pw, true
+	}},
+	"weight":    {title: "weight", sams: func(sc *samples.SamplesCollection) *samples.Samples { return sc.WeightSamples }},
+	"psp":       {title: "psp", sams: func(sc *samples.SamplesCollection) *samples.Samples { return sc.PspSamples }},
+	"surge":     {title: "surge", sams: func(sc *samples.SamplesCollection) *samples.Samples { return sc.SurgeSamples }},
+	"neuronpsp": {title: "neuron psp", neuron: true, sams: func(sc *samples.SamplesCollection) *samples.Samples { return sc.NeuronPspSamples }},
+	"apfast":    {title: "AP fast", neuron: true, sams: func(sc *samples.SamplesCollection) *samples.Samples { return sc.NeuronAPSamples }},
+	"apslow":    {title: "AP slow", neuron: true, sams: func(sc *samples.SamplesCollection) *samples.Samples { return sc.NeuronAPSlowSamples }},
+	"neurondt":  {title: "neuron dt", neuron: true, sams: func(sc *samples.SamplesCollection) *samples.Samples { return sc.NeuronDtSamples }},
+}
+
+// value is the series at time t of a synapse, false if there isn't one.
+func (s series) value(sc *samples.SamplesCollection, syn, t int) (float64, bool) {
+	if s.derive != nil {
+		return s.derive(sc, syn, t)
+	}
+
+	if s.neuron {
+		syn = 0
+	}
+
+	return laneValue(s.sams(sc), syn, t)
+}
+
+// lane is the synapse's lane, nil for derived series.
+func (s series) lane(sc *samples.SamplesCollection, syn int) *samples.SamplesLane {
+	if s.sams == nil {
+		return nil
+	}
+
+	if s.neuron {
+		syn = 0
+	}
+
+	lane, ok := s.sams(sc).GetLanes().Get(syn)
+	if !ok {
+		return nil
+	}
+
+	return lane.(*samples.SamplesLane)
+}
+
+// laneValue is the float sample of a lane at time t.
+func laneValue(sams *samples.Samples, id, t int) (float64, bool) {
+	lane, ok := sams.GetLanes().Get(id)
+	if !ok {
+		return 0, false
+	}
+
+	values := lane.(*samples.SamplesLane).Values
+	if t < 0 || t >= len(values) {
+		return 0, false
+	}
+
+	v, ok := values[t].Value.(float64)
+	return v, ok
+}
@@ -0,0 +1,82 @@
+package graphs
+
+import (
+	"image"
+
+	"github.com/fogleman/gg"
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/wdevore/Deuron5/deuron/app/events"
+	"github.com/wdevore/Deuron5/deuron/app/gui"
+	"github.com/wdevore/Deuron5/simulation/bench"
+)
+
+// STDPGraph shows the learning window measured by the STDP bench: Δw
+// against Δt, a curve per pairing frequency.
+type STDPGraph struct {
+	BaseGraph
+	gui.BaseWidget
+
+	dc     *gg.Context
+	pixels *image.RGBA
+
+	points  []*bench.Point
+	visible bool
+}
+
+func NewSTDPGraph(renderer *sdl.Renderer, texture *sdl.Texture, width, height int) IGraph {
+	g := new(STDPGraph)
+	g.BaseWidget.Initialize(nil, width, height)
+	g.SetGraphics(renderer, texture)
+
+	g.dc = gg.NewContext(width, height)
+	g.pixels = g.dc.Image().(*image.RGBA)
+
+	return g
+}
+
+// SetPoints replaces the window being shown.
+func (g *STDPGraph) SetPoints(points []*bench.Point) {
+	g.points = points
+}
+
+func (g *STDPGraph) SetVisible(visible bool) {
+	g.visible = visible
+}
+
+func (g *STDPGraph) Visible() bool {
+	return g.visible
+}
+
+func (g *STDPGraph) Handle(x, y int32, eventType events.MouseEventType) (handled bool, id int) {
+	return false, -1
+}
+
+func (g *STDPGraph) SetSeries(accessor SeriesAccessor) {
+}
+
+// Destroy release resources
+func (g *STDPGraph) Destroy() {
+}
+
+// Draw renders graph to texture
+func (g *STDPGraph) Draw() {
+	bench.Draw(g.dc, g.points)
+
+	// Offscreen graphs have nowhere to blit to.
+	if g.texture == nil {
+		return
+	}
+
+	g.texture.Update(&g.Rect, g.pixels.Pix, g.pixels.Stride)
+
+	// Now copy the texture onto the target (aka the display)
+	g.renderer.Copy(g.texture, &g.Rect, &g.Rect)
+}
+
+func (g *STDPGraph) Check() bool {
+	return g.visible && len(g.points) > 0
+}
+
+func (g *STDPGraph) Image() *image.RGBA {
+	return g.pixels
+}
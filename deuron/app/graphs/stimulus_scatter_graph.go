@@ -10,7 +10,6 @@ import (
 	sll "github.com/emirpasic/gods/lists/singlylinkedlist"
 
 	"github.com/veandco/go-sdl2/sdl"
-	"github.com/wdevore/Deuron5/deuron"
 	"github.com/wdevore/Deuron5/deuron/app/comm"
 	"github.com/wdevore/Deuron5/deuron/app/gui"
 	"github.com/wdevore/Deuron5/simulation/samples"
@@ -327,29 +326,13 @@ func (g *StimulusScatterGraph) Check() bool {
 }
 
 func (g *StimulusScatterGraph) setScanWindow(lanes *samples.Samples) {
-	// If RangeSync is enabled then we use the Model's range
-	sync := deuron.SimModel.GetFloat("RangeSync")
-
-	if sync == 1 {
-		g.scanStart = int(deuron.SimModel.GetFloat("Range_Start"))
-		g.scanEnd = int(deuron.SimModel.GetFloat("Range_End"))
-	} else {
-		g.scanStart, g.scanEnd = lanes.GetRange()
-	}
+	g.scanStart, g.scanEnd = g.scanRange(lanes)
 
 	g.scanIdx = g.scanStart
 }
 
 func (g *StimulusScatterGraph) setStimScanWindow(lanes *samples.Samples) {
-	// If RangeSync is enabled then we use the Model's range
-	sync := deuron.SimModel.GetFloat("RangeSync")
-
-	if sync == 1 {
-		g.stimScanStart = int(deuron.SimModel.GetFloat("Range_Start"))
-		g.stimScanEnd = int(deuron.SimModel.GetFloat("Range_End"))
-	} else {
-		g.stimScanStart, g.stimScanEnd = lanes.GetRange()
-	}
+	g.stimScanStart, g.stimScanEnd = g.scanRange(lanes)
 
 	g.stimScanIdx = g.stimScanStart
 }
@@ -0,0 +1,163 @@
+package graphs
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/wdevore/Deuron5/deuron/app/comm"
+	"github.com/wdevore/Deuron5/deuron/app/events"
+	"github.com/wdevore/Deuron5/deuron/app/gui"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+// TraceGraph is any series over time for the active synapse, which lets a
+// layout show a sample set that doesn't have a graph of its own.
+type TraceGraph struct {
+	BaseGraph
+	gui.BaseWidget
+
+	lineColor color.RGBA
+
+	series string
+
+	min, max float64
+}
+
+func NewTraceGraph(renderer *sdl.Renderer, texture *sdl.Texture, width, height int, series string) IGraph {
+	g := new(TraceGraph)
+	g.BaseWidget.Initialize(g, width, height)
+	g.BaseGraph.Initialize(g.Rect, g.DC)
+	g.SetGraphics(renderer, texture)
+
+	g.lineColor = color.RGBA{255, 127, 0, 255}
+
+	g.series = SeriesNames[0]
+	if _, ok := seriesByName[series]; ok {
+		g.series = series
+	} else {
+		fmt.Printf("Unknown trace series (%s)\n", series)
+	}
+
+	return g
+}
+
+func (g *TraceGraph) Listen(msg *comm.MessageEvent) {
+	if !g.selected {
+		return
+	}
+
+	// Every sample set has the same range.
+	samples := samples.Sim.CellSamples
+
+	px, py := g.Position()
+	handled := g.handleScroll(msg, samples, px, py, g.Rect)
+
+	if handled {
+		return
+	}
+
+	g.handleRange(msg, samples)
+}
+
+func (g *TraceGraph) Handle(vx, vy int32, eventType events.MouseEventType) (handled bool, id int) {
+	inside := gui.PointInside(vx, vy, g.Rect.X, g.Rect.Y, g.Rect.W, g.Rect.H)
+
+	switch eventType {
+	case events.MouseButton:
+		if inside {
+			g.selected = !g.selected
+			if g.selected {
+				// Send message to app.go
+				comm.MsgBus.Send2("TraceGraph", "Graph", "Selected", "Trace", fmt.Sprintf("%d", g.ID()), "")
+			} else {
+				comm.MsgBus.Send2("TraceGraph", "Graph", "UnSelected", "Trace", fmt.Sprintf("%d", g.ID()), "")
+			}
+			return true, g.ID()
+		}
+		break
+	case events.MouseMotion:
+		handled = g.handleMotion(vx, vy, g.BaseWidget, inside)
+		if handled {
+			return true, g.ID()
+		}
+		break
+	}
+
+	return false, -1
+}
+
+func (g *TraceGraph) SetSeries(accessor SeriesAccessor) {
+}
+
+// Destroy release resources
+func (g *TraceGraph) Destroy() {
+}
+
+// Draw renders graph to texture
+func (g *TraceGraph) Draw() {
+	g.BaseGraph.Draw(g.Rect, g.DC)
+
+	s := seriesByName[g.series]
+	syn := g.activeSynapse()
+
+	g.DC.SetColor(g.lineColor)
+
+	// Steps without a value break the line.
+	first := true
+	for t := g.scanStart; t < g.scanEnd; t++ {
+		v, ok := s.value(samples.Sim, syn, t)
+		if !ok {
+			first = true
+			continue
+		}
+
+		winX := g.Lerp(0, g.upperX, unit(float64(g.scanStart), float64(g.scanEnd), float64(t)))
+		winY := g.Lerp(0, g.upperY, unit(g.min, g.max, v))
+
+		if first {
+			g.DC.MoveTo(winX, winY)
+			first = false
+		} else {
+			g.DC.LineTo(winX, winY)
+		}
+	}
+	g.DC.Stroke()
+
+	// -------------------------------------------
+	// Draw labels and ruler marks
+	// -------------------------------------------
+	g.drawTitle(g.Rect, g.DC)
+	g.drawMinMax(g.min, g.max, g.Rect, g.DC)
+
+	winX := g.drawVerticalTimeBar(g.Rect, g.DC)
+	g.drawMouseInfo(winX, g.Rect, g.DC)
+
+	g.postDraw(g.Rect)
+}
+
+func (g *TraceGraph) Check() bool {
+	sc := samples.Sim
+	if sc == nil {
+		return false
+	}
+
+	g.scanStart, g.scanEnd = g.scanRange(sc.CellSamples)
+
+	s := seriesByName[g.series]
+	syn := g.activeSynapse()
+
+	// Derived series don't have a lane for the mouse to read.
+	g.activeLane = s.lane(sc, syn)
+
+	// Scaled to what's in view.
+	g.min, g.max = math.Inf(1), math.Inf(-1)
+	for t := g.scanStart; t < g.scanEnd; t++ {
+		if v, ok := s.value(sc, syn, t); ok {
+			g.min, g.max = math.Min(g.min, v), math.Max(g.max, v)
+		}
+	}
+
+	return g.max >= g.min
+}
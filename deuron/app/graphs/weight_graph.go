@@ -8,7 +8,6 @@ import (
 
 	sll "github.com/emirpasic/gods/lists/singlylinkedlist"
 	"github.com/veandco/go-sdl2/sdl"
-	"github.com/wdevore/Deuron5/deuron"
 	"github.com/wdevore/Deuron5/deuron/app/comm"
 	"github.com/wdevore/Deuron5/deuron/app/gui"
 	"github.com/wdevore/Deuron5/simulation/samples"
@@ -55,6 +54,11 @@ func (g *WeightGraph) Listen(msg *comm.MessageEvent) {
 		return
 	}
 
+	// Multiple lanes are chosen with the panel's lane fields.
+	if g.laneMode() != LaneSingle && g.handleLaneRange(msg) {
+		return
+	}
+
 	handled = g.handleRange(msg, samples)
 
 	if handled {
@@ -72,13 +76,17 @@ func (g *WeightGraph) Handle(vx, vy int32, eventType events.MouseEventType) (han
 			if g.selected {
 				// Send message to app.go
 				comm.MsgBus.Send2("WeightGraph", "Graph", "Selected", "Surge", fmt.Sprintf("%d", g.ID()), "")
-				// Update gui Range fields the sample's range.
-				samples := samples.Sim.SurgeSamples
-				start, end := samples.GetRange()
-
-				// Send message to panel including the panel's id.
-				comm.MsgBus.Send3("WeightGraph", "Model", "Set", "", "", "Lane_Start", fmt.Sprintf("%d", start))
-				comm.MsgBus.Send3("WeightGraph", "Model", "Set", "", "", "Lane_End", fmt.Sprintf("%d", end))
+				if g.laneMode() != LaneSingle {
+					g.sendLaneSelection("WeightGraph")
+				} else {
+					// Update gui Range fields the sample's range.
+					samples := samples.Sim.SurgeSamples
+					start, end := samples.GetRange()
+
+					// Send message to panel including the panel's id.
+					comm.MsgBus.Send3("WeightGraph", "Model", "Set", "", "", "Lane_Start", fmt.Sprintf("%d", start))
+					comm.MsgBus.Send3("WeightGraph", "Model", "Set", "", "", "Lane_End", fmt.Sprintf("%d", end))
+				}
 			} else {
 				comm.MsgBus.Send2("WeightGraph", "Graph", "UnSelected", "Surge", fmt.Sprintf("%d", g.ID()), "")
 			}
@@ -110,6 +118,17 @@ func (g *WeightGraph) Prep() {
 func (g *WeightGraph) Draw() {
 	g.BaseGraph.Draw(g.Rect, g.DC)
 
+	if mode := g.laneMode(); mode != LaneSingle {
+		g.drawLanes(g.selectedLanes(samples.Sim.WeightSamples), mode, g.Rect, g.DC)
+
+		g.drawTitle(g.Rect, g.DC)
+		winX := g.drawVerticalTimeBar(g.Rect, g.DC)
+		g.drawMouseInfo(winX, g.Rect, g.DC)
+
+		g.postDraw(g.Rect)
+		return
+	}
+
 	if g.activeLane.Min < 0 {
 		g.drawZeroLine(g.Rect, g.DC)
 	}
@@ -179,7 +198,7 @@ func (g *WeightGraph) Check() bool {
 	g.graphIt = lanes.Iterator()
 
 	if g.graphIt.First() {
-		activeSynID := int(deuron.SimModel.GetFloat("Active_Synapse"))
+		activeSynID := g.activeSynapse()
 		lane, _ := lanes.Get(activeSynID)
 		g.activeLane = lane.(*samples.SamplesLane)
 		g.setScanWindow(samples.Sim.WeightSamples)
@@ -190,14 +209,7 @@ func (g *WeightGraph) Check() bool {
 }
 
 func (g *WeightGraph) setScanWindow(lanes *samples.Samples) {
-	// If RangeSync is enabled then we use the Model's range
-	sync := deuron.SimModel.GetFloat("RangeSync")
-	if sync == 1 {
-		g.scanStart = int(deuron.SimModel.GetFloat("Range_Start"))
-		g.scanEnd = int(deuron.SimModel.GetFloat("Range_End"))
-	} else {
-		g.scanStart, g.scanEnd = lanes.GetRange()
-	}
+	g.scanStart, g.scanEnd = g.scanRange(lanes)
 	g.scanIdx = g.scanStart
 }
 
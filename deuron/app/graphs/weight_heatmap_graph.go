@@ -0,0 +1,181 @@
+package graphs
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/wdevore/Deuron5/deuron/app/comm"
+	"github.com/wdevore/Deuron5/deuron/app/events"
+	"github.com/wdevore/Deuron5/deuron/app/gui"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+// Pixels above the cells for the labels.
+const heatmapHeader = 18
+
+// WeightHeatmapGraph shows every synapse's weight over the range, a row
+// per synapse starting with 0 at the top. The active synapse is outlined.
+type WeightHeatmapGraph struct {
+	BaseGraph
+	gui.BaseWidget
+
+	min, max float64
+	lanes    []*samples.SamplesLane
+}
+
+func NewWeightHeatmapGraph(renderer *sdl.Renderer, texture *sdl.Texture, width, height int) IGraph {
+	g := new(WeightHeatmapGraph)
+	g.BaseWidget.Initialize(g, width, height)
+	g.BaseGraph.Initialize(g.Rect, g.DC)
+	g.SetGraphics(renderer, texture)
+
+	return g
+}
+
+func (g *WeightHeatmapGraph) Listen(msg *comm.MessageEvent) {
+	if !g.selected {
+		return
+	}
+
+	samples := samples.Sim.WeightSamples
+
+	px, py := g.Position()
+	handled := g.handleScroll(msg, samples, px, py, g.Rect)
+
+	if handled {
+		return
+	}
+
+	g.handleRange(msg, samples)
+}
+
+func (g *WeightHeatmapGraph) Handle(vx, vy int32, eventType events.MouseEventType) (handled bool, id int) {
+	inside := gui.PointInside(vx, vy, g.Rect.X, g.Rect.Y, g.Rect.W, g.Rect.H)
+
+	switch eventType {
+	case events.MouseButton:
+		if inside {
+			g.selected = !g.selected
+			if g.selected {
+				// Send message to app.go
+				comm.MsgBus.Send2("WeightHeatmapGraph", "Graph", "Selected", "Weight", fmt.Sprintf("%d", g.ID()), "")
+				samples := samples.Sim.WeightSamples
+				start, end := samples.GetRange()
+
+				comm.MsgBus.Send3("WeightHeatmapGraph", "Model", "Set", "", "", "Lane_Start", fmt.Sprintf("%d", start))
+				comm.MsgBus.Send3("WeightHeatmapGraph", "Model", "Set", "", "", "Lane_End", fmt.Sprintf("%d", end))
+			} else {
+				comm.MsgBus.Send2("WeightHeatmapGraph", "Graph", "UnSelected", "Weight", fmt.Sprintf("%d", g.ID()), "")
+			}
+			return true, g.ID()
+		}
+		break
+	case events.MouseMotion:
+		handled = g.handleMotion(vx, vy, g.BaseWidget, inside)
+		if handled {
+			return true, g.ID()
+		}
+		break
+	}
+
+	return false, -1
+}
+
+func (g *WeightHeatmapGraph) SetSeries(accessor SeriesAccessor) {
+}
+
+// Destroy release resources
+func (g *WeightHeatmapGraph) Destroy() {
+}
+
+// Draw renders graph to texture
+func (g *WeightHeatmapGraph) Draw() {
+	g.BaseGraph.Draw(g.Rect, g.DC)
+
+	// The cells are written straight into the pixels, inside the border
+	// and below the labels, rather than filling thousands of rectangles.
+	left := int(g.borderOffsetX) + 1
+	top := int(g.borderOffsetY) + heatmapHeader
+	width := int(g.upperX) - 1
+	height := int(g.upperY) - heatmapHeader
+	rowH := float64(height) / float64(len(g.lanes))
+
+	for x := 0; x < width; x++ {
+		t := g.scanStart + int(float64(x)/float64(width)*float64(g.scanEnd-g.scanStart))
+
+		for row, lane := range g.lanes {
+			if t >= len(lane.Values) || lane.Values[t].Value == nil {
+				continue
+			}
+
+			c := heatColor(g.Linear(g.min, g.max, lane.Values[t].Value.(float64)))
+			for y := int(float64(row) * rowH); y < int(float64(row+1)*rowH); y++ {
+				g.pixels.SetRGBA(left+x, top+y, c)
+			}
+		}
+	}
+
+	// Outline the active synapse. Graph-space has y up.
+	active := g.activeSynapse()
+	if active >= 0 && active < len(g.lanes) {
+		g.DC.SetColor(g.selectedBarColor)
+		g.DC.DrawRectangle(1, float64(height)-float64(active+1)*rowH, g.upperX-2, rowH)
+		g.DC.Stroke()
+	}
+
+	// -------------------------------------------
+	// Draw labels and ruler marks
+	// -------------------------------------------
+	g.drawTitle(g.Rect, g.DC)
+
+	g.DC.Identity()
+	g.DC.Translate(g.borderOffsetX, g.borderOffsetY)
+	g.DC.SetColor(g.maxTextColor)
+	g.DC.DrawString(fmt.Sprintf("synapses: %d, w: [%0.3f, %0.3f]", len(g.lanes), g.min, g.max),
+		5, float64(g.Rect.H)-g.upperY+g.borderOffsetY)
+
+	g.drawVerticalTimeBar(g.Rect, g.DC)
+
+	g.postDraw(g.Rect)
+}
+
+func (g *WeightHeatmapGraph) Check() bool {
+	if samples.Sim == nil || samples.Sim.WeightSamples == nil {
+		return false
+	}
+
+	ws := samples.Sim.WeightSamples
+
+	g.lanes = g.lanes[:0]
+	it := ws.GetLanes().Iterator()
+	for it.Next() {
+		g.lanes = append(g.lanes, it.Value().(*samples.SamplesLane))
+	}
+
+	g.scanStart, g.scanEnd = g.scanRange(ws)
+	g.min, g.max = weightRange(ws)
+
+	return len(g.lanes) > 0 && g.scanEnd > g.scanStart
+}
+
+// heatColor maps 0 -> 1 from dark blue through orange to pale yellow.
+func heatColor(u float64) color.RGBA {
+	if u < 0 {
+		u = 0
+	} else if u > 1 {
+		u = 1
+	}
+
+	lerp := func(a, b uint8, t float64) uint8 {
+		return uint8(float64(a)*(1-t) + float64(b)*t)
+	}
+
+	if u < 0.5 {
+		t := u * 2
+		return color.RGBA{lerp(16, 255, t), lerp(16, 127, t), lerp(96, 0, t), 255}
+	}
+
+	t := (u - 0.5) * 2
+	return color.RGBA{255, lerp(127, 255, t), lerp(0, 160, t), 255}
+}
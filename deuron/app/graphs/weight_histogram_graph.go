@@ -0,0 +1,191 @@
+package graphs
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/wdevore/Deuron5/deuron"
+	"github.com/wdevore/Deuron5/deuron/app/comm"
+	"github.com/wdevore/Deuron5/deuron/app/events"
+	"github.com/wdevore/Deuron5/deuron/app/gui"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+const weightBins = 20
+
+// WeightHistogramGraph counts the weights of every synapse at the end of
+// the range, excititory and inhibitory side by side.
+type WeightHistogramGraph struct {
+	BaseGraph
+	gui.BaseWidget
+
+	excColor color.RGBA
+	inhColor color.RGBA
+
+	min, max float64
+	hist     map[int][]int
+}
+
+func NewWeightHistogramGraph(renderer *sdl.Renderer, texture *sdl.Texture, width, height int) IGraph {
+	g := new(WeightHistogramGraph)
+	g.BaseWidget.Initialize(g, width, height)
+	g.BaseGraph.Initialize(g.Rect, g.DC)
+	g.SetGraphics(renderer, texture)
+
+	g.excColor = color.RGBA{255, 127, 0, 255}
+	g.inhColor = color.RGBA{64, 127, 255, 255}
+
+	return g
+}
+
+func (g *WeightHistogramGraph) Listen(msg *comm.MessageEvent) {
+	if !g.selected {
+		return
+	}
+
+	samples := samples.Sim.WeightSamples
+
+	px, py := g.Position()
+	handled := g.handleScroll(msg, samples, px, py, g.Rect)
+
+	if handled {
+		return
+	}
+
+	g.handleRange(msg, samples)
+}
+
+func (g *WeightHistogramGraph) Handle(vx, vy int32, eventType events.MouseEventType) (handled bool, id int) {
+	inside := gui.PointInside(vx, vy, g.Rect.X, g.Rect.Y, g.Rect.W, g.Rect.H)
+
+	switch eventType {
+	case events.MouseButton:
+		if inside {
+			g.selected = !g.selected
+			if g.selected {
+				// Send message to app.go
+				comm.MsgBus.Send2("WeightHistogramGraph", "Graph", "Selected", "Weight", fmt.Sprintf("%d", g.ID()), "")
+				samples := samples.Sim.WeightSamples
+				start, end := samples.GetRange()
+
+				comm.MsgBus.Send3("WeightHistogramGraph", "Model", "Set", "", "", "Lane_Start", fmt.Sprintf("%d", start))
+				comm.MsgBus.Send3("WeightHistogramGraph", "Model", "Set", "", "", "Lane_End", fmt.Sprintf("%d", end))
+			} else {
+				comm.MsgBus.Send2("WeightHistogramGraph", "Graph", "UnSelected", "Weight", fmt.Sprintf("%d", g.ID()), "")
+			}
+			return true, g.ID()
+		}
+		break
+	}
+
+	return false, -1
+}
+
+// TimeBased is false, the graph isn't of the range.
+func (g *WeightHistogramGraph) TimeBased() bool {
+	return false
+}
+
+func (g *WeightHistogramGraph) SetSeries(accessor SeriesAccessor) {
+}
+
+// Destroy release resources
+func (g *WeightHistogramGraph) Destroy() {
+}
+
+// Draw renders graph to texture
+func (g *WeightHistogramGraph) Draw() {
+	g.BaseGraph.Draw(g.Rect, g.DC)
+
+	exc := g.hist[samples.ExcititoryKey]
+	inh := g.hist[samples.InhibitoryKey]
+
+	most := 1
+	excCnt, inhCnt := 0, 0
+	for i := 0; i < weightBins; i++ {
+		if exc != nil {
+			excCnt += exc[i]
+			if exc[i] > most {
+				most = exc[i]
+			}
+		}
+		if inh != nil {
+			inhCnt += inh[i]
+			if inh[i] > most {
+				most = inh[i]
+			}
+		}
+	}
+
+	binW := g.upperX / weightBins
+
+	bar := func(counts []int, offset float64, c color.RGBA) {
+		if counts == nil {
+			return
+		}
+		g.DC.SetColor(c)
+		for i, n := range counts {
+			if n == 0 {
+				continue
+			}
+			h := g.Lerp(0, g.upperY*0.85, float64(n)/float64(most))
+			g.DC.DrawRectangle(float64(i)*binW+offset, 0, binW/2-1, h)
+			g.DC.Fill()
+		}
+	}
+
+	bar(exc, 1, g.excColor)
+	bar(inh, binW/2, g.inhColor)
+
+	// -------------------------------------------
+	// Draw labels
+	// -------------------------------------------
+	g.drawTitle(g.Rect, g.DC)
+
+	g.DC.Identity()
+	g.DC.Translate(g.borderOffsetX, g.borderOffsetY)
+	top := float64(g.Rect.H) - g.upperY + g.borderOffsetY
+	g.DC.SetColor(g.maxTextColor)
+	g.DC.DrawString(fmt.Sprintf("t: %d, w: [%0.3f, %0.3f], max: %d", g.scanEnd-1, g.min, g.max, most), 5, top)
+	g.DC.SetColor(g.excColor)
+	g.DC.DrawString(fmt.Sprintf("exc: %d", excCnt), 5, top+15)
+	g.DC.SetColor(g.inhColor)
+	g.DC.DrawString(fmt.Sprintf("inh: %d", inhCnt), 5, top+30)
+
+	g.postDraw(g.Rect)
+}
+
+func (g *WeightHistogramGraph) Check() bool {
+	if samples.Sim == nil || samples.Sim.WeightSamples == nil {
+		return false
+	}
+
+	ws := samples.Sim.WeightSamples
+	g.scanStart, g.scanEnd = g.scanRange(ws)
+	g.min, g.max = weightRange(ws)
+
+	g.hist = ws.Histogram(g.scanEnd-1, g.min, g.max, weightBins)
+
+	return len(g.hist) > 0
+}
+
+// weightRange is the model's weight bounds, or the samples' if the model
+// doesn't have any.
+func weightRange(ws *samples.Samples) (min, max float64) {
+	min = deuron.SimModel.GetFloat("weightMin")
+	max = deuron.SimModel.GetFloat("weightMax")
+	if max > min {
+		return min, max
+	}
+
+	min, max = 0, 0
+	it := ws.GetLanes().Iterator()
+	for it.Next() {
+		lane := it.Value().(*samples.SamplesLane)
+		if lane.Max > max {
+			max = lane.Max
+		}
+	}
+	return min, max
+}
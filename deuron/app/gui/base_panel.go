@@ -43,3 +43,10 @@ func (bp *basePanel) update() {
 	// Now copy the texture onto the target (aka the display)
 	bp.renderer.Copy(bp.texture, &bp.Rect, &bp.Rect)
 }
+
+// setTexture changes the texture the panel is drawn to, for example, when
+// the window is resized.
+func (bp *basePanel) setTexture(texture *sdl.Texture) {
+	bp.texture = texture
+	bp.texture.SetBlendMode(sdl.BLENDMODE_BLEND)
+}
@@ -0,0 +1,200 @@
+package gui
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/wdevore/Deuron5/deuron"
+	"github.com/wdevore/Deuron5/deuron/app/comm"
+	"github.com/wdevore/Deuron5/deuron/app/events"
+)
+
+// PanelSpec describes a panel of fields shown by a button on the main
+// panel, for example:
+//
+//	{
+//		"Name": "Km0", "Label": "Main Pan",
+//		"Rows": [
+//			[{"Field": "Active_Synapse", "Label": "Synapse", "MaxField": "Synapse_Count", "MaxOffset": -1},
+//			 {"Field": "threshold", "Label": "Threshold", "Message": "Neuron", "Max": 1000}]
+//		]
+//	}
+type PanelSpec struct {
+	Name  string
+	Label string
+
+	Rows [][]*FieldSpec
+}
+
+// FieldSpec is a Model property. Without a Value it's a ValueButton that
+// edits the property, a number or text, with one it's a Button that sets
+// the property to the Value. The buttons of a panel are a group, only the
+// last one pressed is selected.
+type FieldSpec struct {
+	Field string
+	Label string
+
+	// What the change is about: Simulation, Neuron, Synapse or Dendrite.
+	// The sim passes it on to the part, "" is only for the app.
+	Message string
+
+	Value string
+
+	Min float64
+	Max float64
+
+	// MaxField takes the Max from another property, plus MaxOffset.
+	MaxField  string
+	MaxOffset float64
+}
+
+// FieldPanelWidget is a panel built from a PanelSpec.
+type FieldPanelWidget struct {
+	basePanel
+
+	spec *PanelSpec
+
+	fields  *ValueGroup
+	buttons *ButtonGroup
+}
+
+func NewFieldPanelWidget(spec *PanelSpec, startID int, renderer *sdl.Renderer, texture *sdl.Texture, width, height int) (widget IWidget, id int) {
+	pw := new(FieldPanelWidget)
+	pw.initialize(pw, renderer, texture, width, height)
+	pw.canHide = true
+	pw.id = startID
+	pw.spec = spec
+	iDs := pw.build()
+
+	return pw, iDs
+}
+
+func (pw *FieldPanelWidget) Listen(msg *comm.MessageEvent) {
+	switch msg.Target {
+	case "Panel":
+		switch msg.Action {
+		case "Set":
+			_, widget := pw.fields.values.Find(func(id int, v interface{}) bool {
+				return v.(*ValueButton).ev.Field == msg.Field
+			})
+			if widget != nil {
+				widget.(*ValueButton).SetValue(msg.Value)
+			}
+			break
+		}
+		break
+	}
+}
+
+// override's baseWidget and implements IWidget
+func (pw *FieldPanelWidget) Handle(x, y int32, eventType events.MouseEventType) (handled bool, id int) {
+	if !pw.visible {
+		return false, -1
+	}
+
+	handled, id = pw.fields.Handle(x, y, eventType)
+	if handled {
+		return true, id
+	}
+
+	handled, id = pw.buttons.Handle(x, y, eventType)
+	if handled {
+		return true, id
+	}
+
+	return false, -1
+}
+
+func (pw *FieldPanelWidget) Refresh() {
+	it := pw.fields.Iterator()
+	for it.Next() {
+		btn := it.Value().(*ValueButton)
+		btn.Refresh()
+	}
+}
+
+func (pw *FieldPanelWidget) DrawAt(x, y int32) {
+	pw.SetPos(x, y)
+	pw.Draw()
+}
+
+func (pw *FieldPanelWidget) Draw() {
+	if !pw.visible {
+		return
+	}
+
+	pw.DC.SetColor(pw.backgroundColor)
+	pw.DC.Clear()
+
+	// Draw child widgets
+	it := pw.fields.Iterator()
+	for it.Next() {
+		btn := it.Value().(*ValueButton)
+		btn.Draw()
+	}
+
+	it = pw.buttons.Iterator()
+	for it.Next() {
+		btn := it.Value().(*Button)
+		btn.Draw()
+	}
+
+	pw.update()
+}
+
+func (pw *FieldPanelWidget) build() int {
+	pw.fields = NewValueGroup()
+	pw.buttons = NewButtonGroup()
+
+	iDs := pw.id
+	btnYPos := int32(0)
+
+	for _, row := range pw.spec.Rows {
+		btnXPos := int32(0)
+		rowHeight := int32(0)
+
+		for _, field := range row {
+			var wigBut IWidget
+			var w, h int32
+
+			if field.Value != "" {
+				w, h = DefaultButtonWidth, DefaultButtonHeight
+				wigBut = NewButton(pw, int(w), int(h))
+				btn := wigBut.(*Button)
+				btn.ev.Target = "Model"
+				btn.ev.Action = "Set"
+				btn.ev.Field = field.Field
+				btn.ev.Value = field.Value
+				btn.ev.Message = field.Message
+				btn.SetLabel(field.Label)
+				pw.buttons.AddButton(wigBut)
+			} else {
+				w, h = DefaultValueButtonWidth+50, DefaultValueButtonHeight
+				wigBut = NewValueButton(pw, int(w), int(h))
+				btn := wigBut.(*ValueButton)
+				btn.ev.Message = field.Message
+				btn.ev.Field = field.Field
+				btn.Min = field.Min
+				btn.Max = field.Max
+				btn.MaxField = field.MaxField
+				btn.MaxOffset = field.MaxOffset
+				btn.SetLabel(field.Label)
+				btn.SetValue(deuron.SimModel.GetAsString(btn.ev.Field))
+				pw.fields.AddWidget(wigBut)
+			}
+
+			wigBut.SetPos(btnXPos, btnYPos)
+			iDs++
+			wigBut.SetID(iDs)
+
+			btnXPos = btnXPos + w
+			if h > rowHeight {
+				rowHeight = h
+			}
+		}
+
+		btnYPos = btnYPos + rowHeight
+	}
+
+	iDs++
+
+	return iDs
+}
@@ -7,46 +7,79 @@ import (
 	"github.com/wdevore/Deuron5/deuron/app/events"
 )
 
+// Where the main panel goes when the window is wide enough.
+const mainPanelX = 500
+
 // Contains panels and graphs
 type Gui struct {
 	mainPanel IWidget
-	km0Panel  IWidget
-	km1Panel  IWidget
-	km2Panel  IWidget
+
+	// The field panels by name, the main panel's buttons show them.
+	fieldPanels map[string]IWidget
 
 	visiblePanel IWidget
 
 	panels *sll.List
 }
 
-func NewGui(renderer *sdl.Renderer, texture *sdl.Texture, width, height int) *Gui {
+// NewGui creates the main panel and a panel for each spec, the first of
+// which is shown.
+func NewGui(renderer *sdl.Renderer, texture *sdl.Texture, width, height int, specs []*PanelSpec) *Gui {
 	g := new(Gui)
 
 	g.panels = sll.New()
+	g.fieldPanels = map[string]IWidget{}
 
 	controlIDs := 0
-	g.mainPanel, controlIDs = NewPanelWidget(controlIDs, renderer, texture, 1500, 50)
-	g.mainPanel.SetPos(500, 0)
+	g.mainPanel, controlIDs = NewPanelWidget(specs, controlIDs, renderer, texture, 1500, 50)
+	g.mainPanel.SetPos(mainPanelX, 0)
 	g.panels.Add(g.mainPanel)
 
-	g.km0Panel, controlIDs = NewKm0PanelWidget(controlIDs+1, renderer, texture, 1000, 500)
-	g.km0Panel.SetPos(1000, 55)
-	g.km0Panel.Show()
-	g.visiblePanel = g.km0Panel
-	g.panels.Add(g.km0Panel)
-
-	g.km1Panel, controlIDs = NewKm1PanelWidget(controlIDs+1, renderer, texture, 1000, 500)
-	g.km1Panel.SetPos(1000, 55)
-	g.panels.Add(g.km1Panel)
-
-	g.km2Panel, controlIDs = NewKm2PanelWidget(controlIDs+1, renderer, texture, 1000, 500)
-	g.km2Panel.SetPos(1000, 55)
-	g.panels.Add(g.km2Panel)
+	for i, spec := range specs {
+		var panel IWidget
+		panel, controlIDs = NewFieldPanelWidget(spec, controlIDs+1, renderer, texture, 1000, 500)
+		panel.SetPos(1000, 55)
+		if i == 0 {
+			panel.Show()
+			g.visiblePanel = panel
+		}
+		g.fieldPanels[spec.Name] = panel
+		g.panels.Add(panel)
+	}
 
 	// fmt.Printf("IDs generated: %d\n", controlIDs+1)
 	return g
 }
 
+// Resize fits the panels to a window width wide, drawn to texture. The
+// main panel stays beside the status text while it fits, the field panels
+// keep to the right.
+func (g *Gui) Resize(texture *sdl.Texture, width int32) {
+	it := g.panels.Iterator()
+	for it.Next() {
+		it.Value().(interface{ setTexture(*sdl.Texture) }).setTexture(texture)
+	}
+
+	x := width - g.mainPanel.(*PanelWidget).contentWidth
+	if x > mainPanelX {
+		x = mainPanelX
+	}
+	if x < 0 {
+		x = 0
+	}
+	_, y := g.mainPanel.Position()
+	g.mainPanel.SetPos(x, y)
+
+	for _, panel := range g.fieldPanels {
+		x := width - panel.(*FieldPanelWidget).Rect.W
+		if x < 0 {
+			x = 0
+		}
+		_, y := panel.Position()
+		panel.SetPos(x, y)
+	}
+}
+
 func (g *Gui) Handle(vx, vy int32, eventType events.MouseEventType) bool {
 	it := g.panels.Iterator()
 	for it.Next() {
@@ -109,25 +142,18 @@ func (g *Gui) Listen(msg *comm.MessageEvent) {
 				p.Hide()
 			}
 
-			switch msg.Value {
-			case "Km0":
-				g.visiblePanel = g.km0Panel
-				g.visiblePanel.(IWidget).Show()
-				return
-			case "Km1":
-				g.visiblePanel = g.km1Panel
-				g.visiblePanel.(IWidget).Show()
-				return
-			case "Km2":
-				g.visiblePanel = g.km2Panel
-				g.visiblePanel.(IWidget).Show()
+			if panel, ok := g.fieldPanels[msg.Value]; ok {
+				g.visiblePanel = panel
+				g.visiblePanel.Show()
 				return
 			}
 			// fmt.Printf("%s = %s\n", msg.Message, msg.Value)
 			// m.SetAsFloat(msg.Message, msg.Value)
 			return
 		case "Hide":
-			g.visiblePanel.Hide()
+			if g.visiblePanel != nil {
+				g.visiblePanel.Hide()
+			}
 			return
 		}
 	}
@@ -7,8 +7,8 @@ import (
 	"github.com/wdevore/Deuron5/deuron/app/events"
 )
 
-// Layout:
-// |  Run  |  RunPause  |  Pause  |  Stop  |  Resume  |  Km0  |  Km1  |  Km2  |  Km3  |
+// Layout, the panel buttons are those of the layout file:
+// |  Run  |  RunPause  |  Pause  |  Stop  |  Resume  |  Hide  |  Km0  |  Km1  |  Km2  |
 // |  .001 |  .01       |  .1     |  .5    |  1       |  5    |  10   |  25   |  100  |
 
 type PanelWidget struct {
@@ -22,13 +22,20 @@ type PanelWidget struct {
 	// Toggle buttons
 	autoRunPause IWidget
 	rangeSync    IWidget
+
+	// The field panels the keymap buttons show.
+	specs []*PanelSpec
+
+	// How far right the buttons go.
+	contentWidth int32
 }
 
-func NewPanelWidget(startID int, renderer *sdl.Renderer, texture *sdl.Texture, width, height int) (widget IWidget, id int) {
+func NewPanelWidget(specs []*PanelSpec, startID int, renderer *sdl.Renderer, texture *sdl.Texture, width, height int) (widget IWidget, id int) {
 	pw := new(PanelWidget)
 	pw.initialize(pw, renderer, texture, width, height)
 	pw.visible = true
 	pw.id = startID
+	pw.specs = specs
 	iDs := pw.build()
 
 	return pw, iDs
@@ -71,6 +78,15 @@ func (pw *PanelWidget) Handle(x, y int32, eventType events.MouseEventType) (hand
 }
 
 func (pw *PanelWidget) Listen(msg *comm.MessageEvent) {
+	// RangeSync can be turned on by zooming the graphs.
+	if msg.Target == "Data" && msg.Action == "Changed" && msg.Field == "RangeSync" {
+		togBtn := pw.rangeSync.(*ToggleButton)
+		if deuron.SimModel.GetFloat("RangeSync") == 1.0 {
+			togBtn.Select()
+		} else {
+			togBtn.UnSelect()
+		}
+	}
 }
 
 func (pw *PanelWidget) DrawAt(x, y int32) {
@@ -128,8 +144,9 @@ func (pw *PanelWidget) build() int {
 	btnXPos = btnXPos + DefaultButtonWidth*7
 	iDs = pw.build_panel_buttons(iDs, btnXPos, btnYPos)
 
-	btnXPos = btnXPos + DefaultButtonWidth*6
+	btnXPos = btnXPos + DefaultButtonWidth*int32(len(pw.specs)+2)
 	iDs = pw.build_saveload_buttons(iDs, btnXPos, btnYPos)
+	pw.contentWidth = btnXPos + DefaultButtonWidth*2
 
 	btnXPos = 0
 	btnYPos = DefaultButtonHeight
@@ -245,58 +262,24 @@ func (pw *PanelWidget) build_panel_buttons(iDs int, btnXPos, btnYPos int32) int
 	wigBut.SetID(iDs)
 	pw.keymapGroup.AddButton(wigBut)
 
-	btnXPos = btnXPos + DefaultButtonWidth
-	wigBut = NewButton(pw, DefaultButtonWidth, DefaultButtonHeight)
-	wigBut.SetPos(btnXPos, btnYPos)
-	btn = wigBut.(*Button)
-	btn.ev.Target = "Gui"
-	btn.ev.Action = "Show"
-	btn.ev.Message = "Panel"
-	btn.ev.Value = "Km0"
-	btn.Select()
-	btn.SetLabel("Main Pan")
-	iDs++
-	wigBut.SetID(iDs)
-	pw.keymapGroup.AddButton(wigBut)
-
-	btnXPos = btnXPos + DefaultButtonWidth
-	wigBut = NewButton(pw, DefaultButtonWidth, DefaultButtonHeight)
-	wigBut.SetPos(btnXPos, btnYPos)
-	btn = wigBut.(*Button)
-	btn.ev.Target = "Gui"
-	btn.ev.Action = "Show"
-	btn.ev.Message = "Panel"
-	btn.ev.Value = "Km1"
-	btn.SetLabel("Misc Pan")
-	iDs++
-	wigBut.SetID(iDs)
-	pw.keymapGroup.AddButton(wigBut)
-
-	btnXPos = btnXPos + DefaultButtonWidth
-	wigBut = NewButton(pw, DefaultButtonWidth, DefaultButtonHeight)
-	wigBut.SetPos(btnXPos, btnYPos)
-	btn = wigBut.(*Button)
-	btn.ev.Target = "Gui"
-	btn.ev.Action = "Show"
-	btn.ev.Message = "Panel"
-	btn.ev.Value = "Km2"
-	btn.SetLabel("Stims Pan")
-	iDs++
-	wigBut.SetID(iDs)
-	pw.keymapGroup.AddButton(wigBut)
-
-	btnXPos = btnXPos + DefaultButtonWidth
-	wigBut = NewButton(pw, DefaultButtonWidth, DefaultButtonHeight)
-	wigBut.SetPos(btnXPos, btnYPos)
-	btn = wigBut.(*Button)
-	btn.ev.Target = "Gui"
-	btn.ev.Action = "Show"
-	btn.ev.Message = "Panel"
-	btn.ev.Value = "Panel3"
-	btn.SetLabel(btn.ev.Value)
-	iDs++
-	wigBut.SetID(iDs)
-	pw.keymapGroup.AddButton(wigBut)
+	// A button for each field panel, the first is shown to begin with.
+	for i, spec := range pw.specs {
+		btnXPos = btnXPos + DefaultButtonWidth
+		wigBut = NewButton(pw, DefaultButtonWidth, DefaultButtonHeight)
+		wigBut.SetPos(btnXPos, btnYPos)
+		btn = wigBut.(*Button)
+		btn.ev.Target = "Gui"
+		btn.ev.Action = "Show"
+		btn.ev.Message = "Panel"
+		btn.ev.Value = spec.Name
+		if i == 0 {
+			btn.Select()
+		}
+		btn.SetLabel(spec.Label)
+		iDs++
+		wigBut.SetID(iDs)
+		pw.keymapGroup.AddButton(wigBut)
+	}
 
 	iDs++
 
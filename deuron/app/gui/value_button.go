@@ -3,6 +3,9 @@ package gui
 import (
 	"fmt"
 	"image/color"
+	"math"
+	"strconv"
+	"strings"
 
 	"github.com/wdevore/Deuron5/deuron/app/events"
 
@@ -15,7 +18,8 @@ const (
 	SubButtonHeight = 25
 )
 
-// Value buttons have three sub-buttons for inc/dec and edit.
+// Value buttons have three sub-buttons for inc/dec and edit. While editing,
+// the app sends the keys typed, see keymaps.go.
 
 type ValueButton struct {
 	baseButton
@@ -26,15 +30,31 @@ type ValueButton struct {
 	valueColor    color.RGBA
 	incDecColor   color.RGBA
 	editTextColor color.RGBA
+	errorColor    color.RGBA
 
 	// The "Escape" key cancels edit.
 	editMode bool
 
 	originalValue string
 
+	// What's being typed and where.
+	text   []rune
+	cursor int
+
+	// Numeric properties only take numbers, others any text.
+	numeric bool
+
+	// Return was pressed on text that can't be set.
+	invalid bool
+
 	// Constraints
 	Constrained bool
 	Min, Max    float64
+
+	// MaxField takes the Max from another property, plus MaxOffset, as
+	// it is when the value changes.
+	MaxField  string
+	MaxOffset float64
 }
 
 func NewValueButton(parent IWidget, width, height int) IWidget {
@@ -44,6 +64,7 @@ func NewValueButton(parent IWidget, width, height int) IWidget {
 	gb.valueColor = color.RGBA{255, 255, 8, 255}
 	gb.incDecColor = color.RGBA{255, 32, 32, 127}
 	gb.editTextColor = color.RGBA{64, 255, 64, 255}
+	gb.errorColor = color.RGBA{255, 64, 64, 255}
 
 	gb.ev.Source = "ValueButton"
 
@@ -54,36 +75,31 @@ func NewValueButton(parent IWidget, width, height int) IWidget {
 }
 
 func (gb *ValueButton) Listen(msg *comm.MessageEvent) {
-	if !(msg.Field == gb.ev.Field || msg.ID == fmt.Sprintf("%d", gb.id)) {
-		return
-	}
-
 	switch msg.Target {
 	// These messages are sent from keymaps.go
 	case "Control":
+		if msg.ID != gb.ev.ID || !gb.editMode {
+			return
+		}
+
 		switch msg.Action {
 		case "Edit":
-			switch msg.Message {
-			case "Cancel":
-				gb.editMode = false
-				gb.SetValue(gb.originalValue)
-				return
-			case "Done":
-				gb.editMode = false
-				comm.MsgBus.Send3(gb.ev.Source, "Model", "Set", gb.ev.Message, gb.ev.ID, gb.ev.Field, gb.ev.Value)
-			case "Current":
-				gb.SetValue(msg.Value)
-				comm.MsgBus.Send2("Gui", "Data", "Changed", "", "", "")
-				return
-			}
+			gb.edit(msg.Message, msg.Value)
+			comm.MsgBus.Send2("Gui", "Data", "Changed", "", "", "")
 			return
 		}
 		break
 	case "Data":
+		if msg.Field != gb.ev.Field {
+			return
+		}
+
 		switch msg.Action {
 		case "Changed":
-			// Update Gui
-			gb.SetValue(msg.Value)
+			// Update Gui, but not over what's being typed.
+			if !gb.editMode {
+				gb.SetValue(msg.Value)
+			}
 			// gb.simUpdate()
 			return
 		}
@@ -91,13 +107,107 @@ func (gb *ValueButton) Listen(msg *comm.MessageEvent) {
 	}
 }
 
+// edit changes the text being typed. Done sets the model to it, if it's
+// valid, and Cancel goes back to the value before editing.
+func (gb *ValueButton) edit(action, text string) {
+	switch action {
+	case "Insert":
+		typed := []rune(text)
+		t := make([]rune, 0, len(gb.text)+len(typed))
+		t = append(t, gb.text[:gb.cursor]...)
+		t = append(t, typed...)
+		gb.text = append(t, gb.text[gb.cursor:]...)
+		gb.cursor += len(typed)
+	case "Backspace":
+		if gb.cursor > 0 {
+			gb.text = append(gb.text[:gb.cursor-1], gb.text[gb.cursor:]...)
+			gb.cursor--
+		}
+	case "Delete":
+		if gb.cursor < len(gb.text) {
+			gb.text = append(gb.text[:gb.cursor], gb.text[gb.cursor+1:]...)
+		}
+	case "Left":
+		if gb.cursor > 0 {
+			gb.cursor--
+		}
+	case "Right":
+		if gb.cursor < len(gb.text) {
+			gb.cursor++
+		}
+	case "Home":
+		gb.cursor = 0
+	case "End":
+		gb.cursor = len(gb.text)
+	case "Done":
+		value := strings.TrimSpace(string(gb.text))
+		if err := gb.validate(value); err != nil {
+			fmt.Println(err)
+			gb.invalid = true
+			return
+		}
+		gb.endEdit()
+		gb.SetValue(value)
+		// Model will relay to listeners about change.
+		comm.MsgBus.Send3(gb.ev.Source, "Model", "Set", gb.ev.Message, gb.ev.ID, gb.ev.Field, value)
+		return
+	case "Cancel":
+		gb.endEdit()
+		gb.SetValue(gb.originalValue)
+		return
+	}
+
+	gb.invalid = false
+	gb.SetValue(string(gb.text))
+}
+
+// validate checks a numeric property's value is a number within Min and
+// Max, and that others aren't empty.
+func (gb *ValueButton) validate(value string) error {
+	if value == "" {
+		return fmt.Errorf("(%s) needs a value", gb.ev.Field)
+	}
+
+	if !gb.numeric {
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("(%s) isn't a number for (%s)", value, gb.ev.Field)
+	}
+
+	// A button without a Min or Max takes any number.
+	max := gb.max()
+	if (gb.Min != 0 || max != 0) && (f < gb.Min || f > max) {
+		return fmt.Errorf("(%s) isn't within [%g, %g] for (%s)", value, gb.Min, max, gb.ev.Field)
+	}
+
+	return nil
+}
+
+// max is Max, or the MaxField property's current value plus MaxOffset.
+func (gb *ValueButton) max() float64 {
+	if gb.MaxField != "" {
+		return deuron.SimModel.GetFloat(gb.MaxField) + gb.MaxOffset
+	}
+	return gb.Max
+}
+
+// endEdit leaves edit mode and tells the app to stop sending keys.
+func (gb *ValueButton) endEdit() {
+	gb.editMode = false
+	gb.invalid = false
+	comm.MsgBus.Send2(gb.ev.Source, "App", "Edit", "End", gb.ev.ID, "")
+}
+
 func (gb *ValueButton) SetValue(value string) {
 	gb.ev.Value = value
 	gb.valueWidth, gb.valueHeight = gb.DC.MeasureString(value)
 }
 
 func (gb *ValueButton) Refresh() {
-	value := deuron.SimModel.GetFloatAsString(gb.ev.Field)
+	value := deuron.SimModel.GetAsString(gb.ev.Field)
 	// fmt.Printf("%s\n", value)
 	gb.SetValue(value)
 }
@@ -115,21 +225,25 @@ func (gb *ValueButton) Handle(vx, vy int32, eventType events.MouseEventType) (ha
 	px, py := gb.parent.Position()
 	lx, ly := ViewSpaceToLocal(vx, vy, px, py)
 
+	// Text properties can only be edited.
+	_, err := strconv.ParseFloat(deuron.SimModel.GetAsString(gb.ev.Field), 64)
+	numeric := err == nil
+
 	// Inc
-	if PointInside(lx, ly, gb.x+gb.Rect.W-SubButtonWidth, gb.y, SubButtonWidth, SubButtonHeight) {
+	if numeric && PointInside(lx, ly, gb.x+gb.Rect.W-SubButtonWidth, gb.y, SubButtonWidth, SubButtonHeight) {
 		// Notify model of a new value.
 		inc := deuron.SimModel.GetFloat("Inc/Dec")
 		fValue := deuron.SimModel.GetFloat(gb.ev.Field)
 		fValue = fValue + inc
 
-		if !gb.Constrained && fValue <= gb.Max {
+		if !gb.Constrained && fValue <= gb.max() {
 			comm.MsgBus.Send3(gb.ev.Source, "Model", "Set", gb.ev.Message, gb.ev.ID, gb.ev.Field, fmt.Sprintf("%0.3f", fValue))
 		}
 		return true, gb.id
 	}
 
 	// Dec
-	if PointInside(lx, ly, gb.x+gb.Rect.W-SubButtonWidth, gb.y+SubButtonHeight, SubButtonWidth, SubButtonHeight) {
+	if numeric && PointInside(lx, ly, gb.x+gb.Rect.W-SubButtonWidth, gb.y+SubButtonHeight, SubButtonWidth, SubButtonHeight) {
 		inc := deuron.SimModel.GetFloat("Inc/Dec")
 		fValue := deuron.SimModel.GetFloat(gb.ev.Field)
 		fValue = fValue - inc
@@ -144,7 +258,18 @@ func (gb *ValueButton) Handle(vx, vy int32, eventType events.MouseEventType) (ha
 	if PointInside(lx, ly, gb.x+gb.Rect.W-SubButtonWidth*2, gb.y+SubButtonHeight, SubButtonWidth, SubButtonHeight) {
 		// Put widget into the "Edit" state. The "Escape" key cancels edit.
 		gb.editMode = true
-		gb.originalValue = deuron.SimModel.GetFloatAsString(gb.ev.Field)
+		gb.invalid = false
+		gb.numeric = numeric
+		gb.originalValue = deuron.SimModel.GetAsString(gb.ev.Field)
+
+		// Numbers are edited in full rather than to 3 places.
+		text := gb.originalValue
+		if numeric {
+			text = strconv.FormatFloat(deuron.SimModel.GetFloat(gb.ev.Field), 'g', -1, 64)
+		}
+		gb.text = []rune(text)
+		gb.cursor = len(gb.text)
+		gb.SetValue(text)
 
 		comm.MsgBus.Send3(gb.ev.Source, "App", "Edit", "Start", gb.ev.ID, gb.ev.Field, gb.originalValue)
 
@@ -183,12 +308,25 @@ func (gb *ValueButton) Draw() {
 	// gb.DC.DrawString(gb.label, float64(gb.x+gb.Rect.W/2)-gb.textWidth/2, float64(gb.y)+gb.textHeight)
 	gb.DC.DrawString(gb.label, float64(gb.x+5), float64(gb.y)+gb.textHeight)
 
-	if gb.editMode {
+	switch {
+	case gb.invalid:
+		gb.DC.SetColor(gb.errorColor)
+	case gb.editMode:
 		gb.DC.SetColor(gb.editTextColor)
-	} else {
+	default:
 		gb.DC.SetColor(gb.valueColor)
 	}
-	gb.DC.DrawString(gb.ev.Value, float64(gb.x+10), float64(gb.y+gb.Rect.H/2)+gb.textHeight/2)
+	valueY := float64(gb.y+gb.Rect.H/2) + gb.textHeight/2
+	gb.DC.DrawString(gb.ev.Value, float64(gb.x+10), valueY)
+
+	// The cursor, before the character it's at.
+	if gb.editMode {
+		w, _ := gb.DC.MeasureString(string(gb.text[:gb.cursor]))
+		cursorX := float64(gb.x+10) + w
+		gb.DC.MoveTo(cursorX, valueY+2)
+		gb.DC.LineTo(cursorX, valueY-gb.textHeight-2)
+		gb.DC.Stroke()
+	}
 }
 
 // func (gb *ValueButton) simUpdate() {
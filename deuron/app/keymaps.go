@@ -2,11 +2,13 @@ package app
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/veandco/go-sdl2/sdl"
 	"github.com/wdevore/Deuron5/deuron"
 	"github.com/wdevore/Deuron5/deuron/app/comm"
 	"github.com/wdevore/Deuron5/deuron/app/events"
+	"github.com/wdevore/Deuron5/deuron/app/graphs"
 )
 
 // filterEvent returns false if it handled the event. Returning false
@@ -17,27 +19,72 @@ func (ap *App) filterEvent(e sdl.Event, userdata interface{}) bool {
 		fmt.Println("SDL Quit event")
 		ap.running = false
 		return false // We handled it. Don't allow it to be added to the queue.
+	case *sdl.WindowEvent:
+		switch t.Event {
+		case sdl.WINDOWEVENT_SIZE_CHANGED:
+			ap.resized = true
+		case sdl.WINDOWEVENT_EXPOSED:
+			ap.dirty = true
+		}
+		return false
 	case *sdl.MouseMotionEvent:
-		ap.mouseX = t.X
-		ap.mouseY = t.Y
-		ap.Handle(t.X, t.Y, events.MouseMotion)
+		// Mouse positions are in the window's points, the widgets are in
+		// the view.
+		ap.mouseX, ap.mouseY = ap.toView(t.X, t.Y)
+		ap.Handle(ap.mouseX, ap.mouseY, events.MouseMotion)
 
 		// fmt.Printf("[%d ms] MouseMotion\ttype:%d\tid:%d\tx:%d\ty:%d\txrel:%d\tyrel:%d\n",
 		// 	t.Timestamp, t.Type, t.Which, t.X, t.Y, t.XRel, t.YRel)
 		return false // We handled it. Don't allow it to be added to the queue.
 	case *sdl.MouseButtonEvent:
-		if t.State == 1 {
-			ap.Handle(t.X, t.Y, events.MouseButton)
-			// fmt.Printf("[%d ms](%v) MouseButton\ttype:%d\tid:%d\tx:%d\ty:%d\tbutton:%d\tstate:%d\n",
-			// 	t.Timestamp, handled, t.Type, t.Which, t.X, t.Y, t.Button, t.State)
+		// Left presses are the clicks, the navigator uses releases and
+		// the right button to drag.
+		x, y := ap.toView(t.X, t.Y)
+		switch t.Button {
+		case sdl.BUTTON_LEFT:
+			if t.State == sdl.PRESSED {
+				ap.Handle(x, y, events.MouseButton)
+			} else {
+				ap.Handle(x, y, events.MouseButtonUp)
+			}
+		case sdl.BUTTON_RIGHT:
+			if t.State == sdl.PRESSED {
+				ap.Handle(x, y, events.MouseRightButton)
+			} else {
+				ap.Handle(x, y, events.MouseRightButtonUp)
+			}
 		}
+		// fmt.Printf("[%d ms] MouseButton\ttype:%d\tid:%d\tx:%d\ty:%d\tbutton:%d\tstate:%d\n",
+		// 	t.Timestamp, t.Type, t.Which, t.X, t.Y, t.Button, t.State)
 		return false
 	case *sdl.MouseWheelEvent:
-		ap.Handle(t.X, t.Y, events.MouseWheel)
+		// The wheel's X, Y are how far it turned, the mouse is where it
+		// last moved to. With shift it scrolls the graphs.
+		if sdl.GetModState()&sdl.KMOD_SHIFT != 0 {
+			ap.scroll(-t.Y * scrollStep)
+			return false
+		}
+
+		if t.Y > 0 {
+			ap.Handle(ap.mouseX, ap.mouseY, events.MouseWheelUp)
+		} else if t.Y < 0 {
+			ap.Handle(ap.mouseX, ap.mouseY, events.MouseWheelDown)
+		}
 		// fmt.Printf("[%d ms] MouseWheel\ttype:%d\tid:%d\tx:%d\ty:%d\n",
 		// 	t.Timestamp, t.Type, t.Which, t.X, t.Y)
 		return false
+	case *sdl.TextInputEvent:
+		// Typed text goes to the value button being edited.
+		if ap.editID != "" {
+			comm.MsgBus.Send2("Keymaps", "Control", "Edit", "Insert", ap.editID, t.GetText())
+		}
+		return false
 	case *sdl.KeyboardEvent:
+		if ap.editID != "" {
+			ap.editKey(t)
+			return false
+		}
+
 		// fmt.Printf("[%d ms] Keyboard\ttype:%d\tsym:%c\tmodifiers:%d\tScode:%d\tstate:%d\trepeat:%d\n",
 		// 	t.Timestamp, t.Type, t.Keysym.Sym, t.Keysym.Mod, t.Keysym.Scancode, t.State, t.Repeat)
 		switch t.Keysym.Scancode {
@@ -62,35 +109,93 @@ func (ap *App) filterEvent(e sdl.Event, userdata interface{}) bool {
 				comm.MsgBus.Send2("Keymaps", "Key", "Right", "ScrollRight", ap.controlID, "")
 			}
 
+			return false
+		case sdl.SCANCODE_UP, sdl.SCANCODE_DOWN, sdl.SCANCODE_PAGEUP, sdl.SCANCODE_PAGEDOWN, sdl.SCANCODE_HOME, sdl.SCANCODE_END:
+			// Scroll the graphs, held keys repeat.
+			if t.State == sdl.PRESSED {
+				page := ap.viewHeight - graphsTop
+				switch t.Keysym.Scancode {
+				case sdl.SCANCODE_UP:
+					ap.scroll(-scrollStep)
+				case sdl.SCANCODE_DOWN:
+					ap.scroll(scrollStep)
+				case sdl.SCANCODE_PAGEUP:
+					ap.scroll(-page)
+				case sdl.SCANCODE_PAGEDOWN:
+					ap.scroll(page)
+				case sdl.SCANCODE_HOME:
+					ap.scroll(-ap.scrollY)
+				case sdl.SCANCODE_END:
+					ap.scroll(ap.graphsBottom)
+				}
+			}
+			return false
+		case sdl.SCANCODE_MINUS, sdl.SCANCODE_EQUALS:
+			// Shrink or grow the fonts and widgets.
+			if t.State == sdl.RELEASED {
+				scale := deuron.SimModel.GetFloat("Scale")
+				if t.Keysym.Scancode == sdl.SCANCODE_MINUS {
+					scale -= 0.1
+				} else {
+					scale += 0.1
+				}
+				scale = math.Max(minScale, math.Min(maxScale, scale))
+				comm.MsgBus.Send3("Keymaps", "Model", "Set", "", "", "Scale", fmt.Sprintf("%0.1f", scale))
+			}
 			return false
 		case sdl.SCANCODE_R:
+			// Run, pause or resume.
 			if t.State == sdl.RELEASED {
 				comm.MsgBus.Send("Keymaps", "App", "Command", "RunPause", "")
 			}
 			return false
+		case sdl.SCANCODE_L:
+			// Cycle the synapse graphs through single, overlay and small
+			// multiples of lanes.
+			if t.State == sdl.RELEASED {
+				mode := (int(deuron.SimModel.GetFloat("Lane_Mode")) + 1) % graphs.LaneModes
+				comm.MsgBus.Send3("Keymaps", "Model", "Set", "", "", "Lane_Mode", fmt.Sprintf("%d", mode))
+				ap.dirty = true
+			}
+			return false
+		case sdl.SCANCODE_F:
+			// Follow the head of a running sim, or not.
+			if t.State == sdl.RELEASED {
+				follow := 1 - int(deuron.SimModel.GetFloat("Follow"))
+				comm.MsgBus.Send3("Keymaps", "Model", "Set", "", "", "Follow", fmt.Sprintf("%d", follow))
+				ap.dirty = true
+			}
+			return false
+		case sdl.SCANCODE_X, sdl.SCANCODE_Y:
+			// Change an axis of the selected scatter graph.
+			if t.State == sdl.RELEASED {
+				axis := "X"
+				if t.Keysym.Scancode == sdl.SCANCODE_Y {
+					axis = "Y"
+				}
+				comm.MsgBus.Send2("Keymaps", "Key", axis, "CycleAxis", ap.controlID, "")
+			}
+			return false
+		case sdl.SCANCODE_P:
+			// Write every graph to a png.
+			if t.State == sdl.RELEASED {
+				comm.MsgBus.Send("Keymaps", "App", "Command", "Snapshot", "")
+			}
+			return false
+		case sdl.SCANCODE_B:
+			// Show or hide the STDP bench's learning window.
+			if t.State == sdl.RELEASED {
+				comm.MsgBus.Send("Keymaps", "App", "Command", "STDPBench", "")
+			}
+			return false
 		}
 
 		if t.State == sdl.RELEASED {
 			switch t.Keysym.Scancode {
-			case sdl.SCANCODE_ESCAPE:
-				// Canceling edit mode on a value button.
-				ap.currentValue = ""
-				comm.MsgBus.Send2("Keymaps", "Control", "Edit", "Cancel", ap.controlID, ap.controlOrgValue)
-				break
 			case sdl.SCANCODE_GRAVE: // The tilte or single quote key
 				fmt.Println("KeyEscape Quit event")
 				ap.running = false
 				break
-			case sdl.SCANCODE_RETURN: // Signals finished entering data
-				// Update control to change modes.
-				comm.MsgBus.Send3("Keymaps", "Control", "Edit", "Done", ap.controlID, ap.controlField, ap.currentValue)
-				// Update model. Model will relay to listeners about change.
-				comm.MsgBus.Send3("Keymaps", "Model", "Set", ap.controlMsg, ap.controlID, ap.controlField, ap.currentValue)
-				break
-			default:
-				ap.currentValue = ap.currentValue + codeToString(t.Keysym.Scancode)
-				comm.MsgBus.Send3("Keymaps", "Control", "Edit", "Current", ap.controlID, ap.controlField, ap.currentValue)
-				break
 			}
 		}
 
@@ -100,6 +205,40 @@ func (ap *App) filterEvent(e sdl.Event, userdata interface{}) bool {
 	return true
 }
 
+// editKey moves the cursor of the value button being edited and deletes
+// with it. Return is done and Escape cancels. The characters typed arrive
+// as text input rather than keys.
+func (ap *App) editKey(t *sdl.KeyboardEvent) {
+	// Presses, so held keys repeat.
+	if t.State != sdl.PRESSED {
+		return
+	}
+
+	action := ""
+	switch t.Keysym.Scancode {
+	case sdl.SCANCODE_BACKSPACE:
+		action = "Backspace"
+	case sdl.SCANCODE_DELETE:
+		action = "Delete"
+	case sdl.SCANCODE_LEFT:
+		action = "Left"
+	case sdl.SCANCODE_RIGHT:
+		action = "Right"
+	case sdl.SCANCODE_HOME:
+		action = "Home"
+	case sdl.SCANCODE_END:
+		action = "End"
+	case sdl.SCANCODE_RETURN, sdl.SCANCODE_KP_ENTER:
+		action = "Done"
+	case sdl.SCANCODE_ESCAPE:
+		action = "Cancel"
+	default:
+		return
+	}
+
+	comm.MsgBus.Send2("Keymaps", "Control", "Edit", action, ap.editID, "")
+}
+
 func (ap *App) rangeScrollLeft() {
 	start := int(deuron.SimModel.GetFloat("Range_Start"))
 	end := int(deuron.SimModel.GetFloat("Range_End"))
@@ -163,31 +302,3 @@ func codeToInt(code sdl.Scancode) int {
 	}
 	return -1
 }
-
-func codeToString(code sdl.Scancode) string {
-	switch code {
-	case sdl.SCANCODE_0:
-		return "0"
-	case sdl.SCANCODE_1:
-		return "1"
-	case sdl.SCANCODE_2:
-		return "2"
-	case sdl.SCANCODE_3:
-		return "3"
-	case sdl.SCANCODE_4:
-		return "4"
-	case sdl.SCANCODE_5:
-		return "5"
-	case sdl.SCANCODE_6:
-		return "6"
-	case sdl.SCANCODE_7:
-		return "7"
-	case sdl.SCANCODE_8:
-		return "8"
-	case sdl.SCANCODE_9:
-		return "9"
-	case sdl.SCANCODE_PERIOD:
-		return "."
-	}
-	return ""
-}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/wdevore/Deuron5/deuron/app/graphs"
+	"github.com/wdevore/Deuron5/deuron/app/gui"
+)
+
+// Layout is the graph stack, top to bottom, and the field panels the app
+// shows. It's read from layout.json so graphs can be added, reordered or
+// hidden, and Model properties given fields, without recompiling.
+type Layout struct {
+	Graphs []*graphs.GraphSpec
+	Panels []*gui.PanelSpec
+}
+
+// A copy of layout.json, what the app shows if layout.json is missing or
+// invalid.
+//
+//go:embed default_layout.json
+var defaultLayout []byte
+
+// DefaultLayout is the layout built into the app.
+func DefaultLayout() *Layout {
+	l, err := parseLayout(defaultLayout)
+	if err != nil {
+		panic(fmt.Sprintf("default layout: %v", err))
+	}
+	return l
+}
+
+// LoadLayout reads a layout from a json file.
+func LoadLayout(fileName string) (*Layout, error) {
+	layoutFile, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer layoutFile.Close()
+
+	byteValue, err := ioutil.ReadAll(layoutFile)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := parseLayout(byteValue)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fileName, err)
+	}
+
+	return l, nil
+}
+
+func parseLayout(byteValue []byte) (*Layout, error) {
+	l := new(Layout)
+	err := json.Unmarshal(byteValue, l)
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Validate checks every graph can be created and every field names a
+// property. The property isn't looked up, some only exist once a graph
+// or the sim sets them.
+func (l *Layout) Validate() error {
+	for i, spec := range l.Graphs {
+		if err := spec.Validate(); err != nil {
+			return fmt.Errorf("graph %d: %v", i, err)
+		}
+	}
+
+	names := map[string]bool{}
+	for i, panel := range l.Panels {
+		if panel.Name == "" {
+			return fmt.Errorf("panel %d needs a Name", i)
+		}
+		if names[panel.Name] {
+			return fmt.Errorf("panel %d: (%s) is used twice", i, panel.Name)
+		}
+		names[panel.Name] = true
+
+		for _, row := range panel.Rows {
+			for _, field := range row {
+				if field.Field == "" {
+					return fmt.Errorf("panel (%s) has a field without a Field", panel.Name)
+				}
+			}
+		}
+	}
+
+	return nil
+}
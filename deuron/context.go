@@ -0,0 +1,115 @@
+package deuron
+
+import (
+	"hash/fnv"
+
+	"github.com/wdevore/Deuron5/deuron/app/comm"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+// SeedDerivation describes how component seeds are made from the master
+// seed. It is recorded along with the seeds in saved output.
+const SeedDerivation = "splitmix64(master XOR fnv1a64(component))"
+
+// Context carries everything a simulation would otherwise get from
+// globals: its Model, samples, message bus, random generator and ids.
+// Cells and streams are given a Context when they are constructed so
+// that several simulations can exist side by side, for example, in a
+// parameter sweep or parallel tests.
+type Context struct {
+	Model   *Model
+	Samples *samples.SamplesCollection
+	Bus     *comm.MessageBus
+	Random  *Random
+
+	// The master seed (the model's "Seed") that every component's
+	// seed is derived from.
+	Seed int64
+
+	// Component name to derived seed, for reproducibility reports.
+	seeds map[string]int64
+
+	gid int
+}
+
+// NewContext creates an isolated context with its own bus and random
+// generator. It takes over the model's bus, the model's changes go on
+// the context's Bus rather than comm.MsgBus, so give it a Clone of a
+// model that the GUI listens to.
+func NewContext(model *Model, sams *samples.SamplesCollection) *Context {
+	c := new(Context)
+	c.Model = model
+	c.Samples = sams
+	c.Bus = comm.NewMessageBus()
+	c.initializeSeeds()
+
+	model.bus = c.Bus
+
+	return c
+}
+
+// DefaultContext is the compatibility context built from the globals
+// SimModel and comm.MsgBus. This is what the GUI's simulation uses, its
+// samples are copied to samples.Sim for the graphs.
+func DefaultContext(sams *samples.SamplesCollection) *Context {
+	c := new(Context)
+	c.Model = SimModel
+	c.Samples = sams
+	c.Bus = comm.MsgBus
+	c.initializeSeeds()
+	return c
+}
+
+func (c *Context) initializeSeeds() {
+	c.Seed = int64(c.Model.GetFloat("Seed"))
+	c.seeds = map[string]int64{}
+	c.Random = c.NewRandom("context")
+}
+
+// DeriveSeed returns the seed for a named component, for example,
+// "synapse/3". The same master seed and name always give the same seed
+// and different names give independent seeds.
+func (c *Context) DeriveSeed(component string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(component))
+
+	seed := int64(splitmix64(uint64(c.Seed) ^ h.Sum64()))
+	c.seeds[component] = seed
+
+	return seed
+}
+
+// NewRandom creates a generator owned by the named component.
+func (c *Context) NewRandom(component string) *Random {
+	return NewRandom(c.DeriveSeed(component))
+}
+
+// SeedReport describes every seed handed out so that a run can be
+// reproduced.
+func (c *Context) SeedReport() map[string]interface{} {
+	components := map[string]interface{}{}
+	for k, v := range c.seeds {
+		components[k] = v
+	}
+
+	return map[string]interface{}{
+		"Master":     c.Seed,
+		"Derivation": SeedDerivation,
+		"Components": components,
+	}
+}
+
+// splitmix64 scrambles x so that nearby inputs give unrelated outputs.
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// NextID returns an id that is unique within this context.
+func (c *Context) NextID() int {
+	g := c.gid
+	c.gid++
+	return g
+}
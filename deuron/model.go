@@ -17,6 +17,9 @@ type Model struct {
 	props *hmap.Map
 
 	mapMutex *sync.Mutex
+
+	// Where property changes are announced.
+	bus *comm.MessageBus
 }
 
 func NewModel() *Model {
@@ -24,6 +27,7 @@ func NewModel() *Model {
 	m.props = hmap.New()
 
 	m.mapMutex = &sync.Mutex{}
+	m.bus = comm.MsgBus
 
 	m.initialize()
 	return m
@@ -55,8 +59,19 @@ func (m *Model) initialize() {
 	m.props.Put("AutoRunPause", 0.0) // 0 = false, 1 = true
 	m.props.Put("RangeSync", 0.0)    // 0 = false, 1 = true
 
+	// While a run is live the graphs show the last Follow_Window ms.
+	m.props.Put("Follow", 0.0) // 0 = false, 1 = true
+	m.props.Put("Follow_Window", 500.0)
+
 	m.props.Put("Inc/Dec", 1.0)
 
+	// Size of the app's fonts and widgets, the window's content is
+	// laid out at its size divided by Scale.
+	m.props.Put("Scale", 1.0)
+
+	// Master seed. Every stream and synapse derives its own seed from it.
+	m.props.Put("Seed", 1963.0)
+
 	// ###############################################################
 	// BEGIN
 	// When one of these properties change then the gui is updated
@@ -90,6 +105,9 @@ func (m *Model) initialize() {
 
 	// Which synapse to focus on visually.
 	m.props.Put("Active_Synapse", 0.0)
+	// 0 = Active_Synapse only, 1 = overlay, 2 = small multiples of the
+	// lanes chosen by Lane_Start/Lane_End.
+	m.props.Put("Lane_Mode", 0.0)
 	m.props.Put("Synapse_Count", 0.0)
 
 	// Synapse specific properties (for all synapses)
@@ -129,14 +147,76 @@ func (m *Model) initialize() {
 	// ###############################################################
 	// END
 	// ###############################################################
+}
 
-	// --------------------------------------------------------------
-	// Experimental properties
-	m.props.Put("ExpoFunc_A", 100.0)
-	m.props.Put("ExpoFunc_Tau", 100.0)
-	m.props.Put("ExpoFunc_M", 5.0)
-	m.props.Put("ExpoFunc_WMax", 20.0)
+// Clone returns an independent copy of the model. Simulations that run
+// headless, for example parameter sweeps, each get their own copy so
+// that changing one doesn't affect the others.
+func (m *Model) Clone() *Model {
+	m.mapMutex.Lock()
+	defer m.mapMutex.Unlock()
+
+	c := new(Model)
+	c.props = hmap.New()
+	c.mapMutex = &sync.Mutex{}
+	c.bus = m.bus
+
+	for _, key := range m.props.Keys() {
+		value, _ := m.props.Get(key)
+		c.props.Put(key, value)
+	}
+
+	return c
+}
+
+// ToJSON returns every property, for example, for a checkpoint.
+func (m *Model) ToJSON() interface{} {
+	m.mapMutex.Lock()
+	defer m.mapMutex.Unlock()
+
+	props := map[string]interface{}{}
+	for _, key := range m.props.Keys() {
+		value, _ := m.props.Get(key)
+		props[key.(string)] = value
+	}
 
+	return props
+}
+
+// Load sets every property found in json, the reverse of ToJSON.
+func (m *Model) Load(json interface{}) {
+	m.mapMutex.Lock()
+	defer m.mapMutex.Unlock()
+
+	for key, value := range json.(map[string]interface{}) {
+		m.props.Put(key, value)
+	}
+}
+
+// LoadSettings populates the model from the app's settings json (neuron.json).
+func (m *Model) LoadSettings(jsonMap map[string]interface{}) {
+	// Calculate sim-duration (aka sample size) based on TimeStep and duration
+	duration := jsonMap["Duration"].(float64)
+	timeStep := jsonMap["TimeStep"].(float64)
+	simDuration := duration * 1000000.0 / timeStep
+	fmt.Printf("Sample size: %d\n", int(simDuration))
+
+	m.SetFloat("TimeStep", timeStep) // microseconds
+	m.SetFloat("Duration", duration) // seconds
+	m.SetFloat("Samples", simDuration)
+
+	m.SetFloat("Range_Start", jsonMap["RangeStart"].(float64))
+	m.SetFloat("Range_End", jsonMap["RangeEnd"].(float64))
+	m.SetString("Stimulus", jsonMap["Stimulus"].(string))
+	m.SetFloat("Synapse_Count", jsonMap["Synapse_Count"].(float64))
+
+	m.SetFloat("weightMin", jsonMap["weightMin"].(float64))
+	m.SetFloat("weightMax", jsonMap["weightMax"].(float64))
+
+	// Older settings don't have a seed.
+	if seed, ok := jsonMap["Seed"]; ok {
+		m.SetFloat("Seed", seed.(float64))
+	}
 }
 
 func (m *Model) Listen(msg *comm.MessageEvent) {
@@ -153,7 +233,7 @@ func (m *Model) Listen(msg *comm.MessageEvent) {
 				m.SetString(msg.Field, msg.Value)
 			}
 			// Notify all listeners that this property changed.
-			comm.MsgBus.Send3("Model", "Data", "Changed", msg.Message, msg.ID, msg.Field, msg.Value)
+			m.bus.Send3("Model", "Data", "Changed", msg.Message, msg.ID, msg.Field, msg.Value)
 			break
 		case "Toggle":
 			fValue := m.GetFloat(msg.Field)
@@ -163,7 +243,7 @@ func (m *Model) Listen(msg *comm.MessageEvent) {
 				fValue = 1.0
 			}
 			m.SetFloat(msg.Field, fValue)
-			comm.MsgBus.Send3("Model", "Data", "Changed", msg.Message, msg.ID, msg.Field, fmt.Sprintf("%0f", fValue))
+			m.bus.Send3("Model", "Data", "Changed", msg.Message, msg.ID, msg.Field, fmt.Sprintf("%0f", fValue))
 			break
 		}
 		break
@@ -230,3 +310,17 @@ func (m *Model) GetFloatAsString(key string) string {
 	}
 	return ""
 }
+
+// GetAsString is a property as text, numbers to 3 places.
+func (m *Model) GetAsString(key string) string {
+	m.mapMutex.Lock()
+	defer m.mapMutex.Unlock()
+	value, found := m.props.Get(key)
+	if !found {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%0.3f", value)
+}
@@ -5,9 +5,96 @@ import (
 	"math"
 	"math/big"
 	"math/rand"
+	"strconv"
+	"sync"
 )
 
-var ran = rand.New(rand.NewSource(1963))
+// Random is a poisson generator with its own source. Each simulation
+// context has one so that simulations don't share random state.
+type Random struct {
+	ran    *rand.Rand
+	source *countingSource
+	seed   int64
+
+	// A Random can still be shared, for example, the default one.
+	mutex *sync.Mutex
+}
+
+// countingSource counts the values drawn since it was seeded. The
+// seed and count are enough to put a source back where it was, which
+// is how a Random is checkpointed.
+type countingSource struct {
+	src   rand.Source64
+	draws uint64
+}
+
+func (cs *countingSource) Int63() int64 {
+	cs.draws++
+	return cs.src.Int63()
+}
+
+func (cs *countingSource) Uint64() uint64 {
+	cs.draws++
+	return cs.src.Uint64()
+}
+
+func (cs *countingSource) Seed(seed int64) {
+	cs.src.Seed(seed)
+	cs.draws = 0
+}
+
+// NewRandom creates a generator seeded with seed.
+func NewRandom(seed int64) *Random {
+	r := new(Random)
+	r.source = &countingSource{src: rand.NewSource(seed).(rand.Source64)}
+	r.ran = rand.New(r.source)
+	r.seed = seed
+	r.mutex = &sync.Mutex{}
+	return r
+}
+
+// State captures the generator's position. int64s don't survive a trip
+// through json's float64 so they are kept as strings.
+func (r *Random) State() interface{} {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return map[string]interface{}{
+		"Seed":  strconv.FormatInt(r.seed, 10),
+		"Draws": strconv.FormatUint(r.source.draws, 10),
+	}
+}
+
+// RestoreState reseeds the generator and replays it to the captured position.
+func (r *Random) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
+
+	seed, err := strconv.ParseInt(jmap["Seed"].(string), 10, 64)
+	if err != nil {
+		panic(err)
+	}
+	draws, err := strconv.ParseUint(jmap["Draws"].(string), 10, 64)
+	if err != nil {
+		panic(err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.ran.Seed(seed)
+	r.seed = seed
+	for r.source.draws < draws {
+		r.source.Int63()
+	}
+}
+
+// The compatibility default used by the package level functions.
+var ran = NewRandom(1963)
+
+// DefaultRandom returns the generator shared by the package level functions.
+func DefaultRandom() *Random {
+	return ran
+}
 
 var poisPMass []float64
 
@@ -23,7 +110,18 @@ func SeedPoisson(seed int64) {
 // A firing rate in rate/ms, for example, 0.2 in 1ms (0.2/1)
 // or 200 in 1sec (200/1000ms)
 func GenPoisson(rate float64) int {
-	p := -math.Log(1.0-ran.Float64()) / rate
+	return ran.GenPoisson(rate)
+}
+
+func (r *Random) Seed(seed int64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.ran.Seed(seed)
+	r.seed = seed
+}
+
+func (r *Random) GenPoisson(rate float64) int {
+	p := -math.Log(1.0-r.Uniform()) / rate
 	return int(p)
 }
 
@@ -54,7 +152,11 @@ func factorial(n *big.Int) (result *big.Int) {
 // SimplePoisson tends to spread spikes a bit more.
 // Typical values of: 15.0, 3.0 yield ISIs 5-7 with occasional 50-100s
 func SimplePoisson(scale, div, min float64) int {
-	return int(scale*math.Pow(math.E, -ran.Float64()*scale/div) + min)
+	return ran.SimplePoisson(scale, div, min)
+}
+
+func (r *Random) SimplePoisson(scale, div, min float64) int {
+	return int(scale*math.Pow(math.E, -r.Uniform()*scale/div) + min)
 }
 
 // func fx(y, l float64) float64 {
@@ -77,17 +179,33 @@ func SimplePoisson(scale, div, min float64) int {
 // https://www.johndcook.com/blog/csharp_log_factorial/
 
 func SetPoissonSeed(seed int64) {
-	ran.Seed(seed)
+	SeedPoisson(seed)
 }
 
 func GetPoisson(lambda float64) int {
+	return ran.GetPoisson(lambda)
+}
+
+func PoissonSmall(lambda float64) int {
+	return ran.PoissonSmall(lambda)
+}
+
+func PoissonLarge(lambda float64) int {
+	return ran.PoissonLarge(lambda)
+}
+
+func GetUniform() float64 {
+	return ran.Uniform()
+}
+
+func (r *Random) GetPoisson(lambda float64) int {
 	if lambda < 30.0 {
-		return PoissonSmall(lambda)
+		return r.PoissonSmall(lambda)
 	}
-	return PoissonLarge(lambda)
+	return r.PoissonLarge(lambda)
 }
 
-func PoissonSmall(lambda float64) int {
+func (r *Random) PoissonSmall(lambda float64) int {
 	// Algorithm due to Donald Knuth, 1969.
 	p := 1.0
 	// L := math.Exp(-lambda)
@@ -95,13 +213,13 @@ func PoissonSmall(lambda float64) int {
 	k := 0
 	for p > L {
 		k++
-		p *= GetUniform()
+		p *= r.Uniform()
 	}
 
 	return k - 1
 }
 
-func PoissonLarge(lambda float64) int {
+func (r *Random) PoissonLarge(lambda float64) int {
 	// "Rejection method PA" from "The Computer Generation of
 	// Poisson Random Variables" by A. C. Atkinson,
 	// Journal of the Royal Statistical Society Series C
@@ -115,13 +233,13 @@ func PoissonLarge(lambda float64) int {
 	k := math.Log(c) - lambda - math.Log(beta)
 
 	for {
-		u := GetUniform()
+		u := r.Uniform()
 		x := (alpha - math.Log((1.0-u)/u)) / beta
 		n := math.Floor(x + 0.5)
 		if n < 0 {
 			continue
 		}
-		v := GetUniform()
+		v := r.Uniform()
 		y := alpha - beta*x
 		temp := 1.0 + math.Exp(y)
 		lhs := y + math.Log(v/(temp*temp))
@@ -136,8 +254,24 @@ func PoissonLarge(lambda float64) int {
 	}
 }
 
-func GetUniform() float64 {
-	return ran.Float64()
+// Uniform returns a value in [0.0, 1.0)
+func (r *Random) Uniform() float64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.ran.Float64()
+}
+
+// Normal is normally distributed with a mean of 0 and standard deviation of 1.
+func (r *Random) Normal() float64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.ran.NormFloat64()
+}
+
+func (r *Random) Int63() int64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.ran.Int63()
 }
 
 var lf = []float64{
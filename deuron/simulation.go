@@ -2,6 +2,7 @@ package deuron
 
 import (
 	"github.com/wdevore/Deuron5/deuron/app/comm"
+	"github.com/wdevore/Deuron5/simulation/samples"
 )
 
 type ISimulation interface {
@@ -15,4 +16,46 @@ type ISimulation interface {
 	SendEvent(event *comm.MessageEvent)
 	ToJSON() interface{}
 	Load(interface{})
+	Checkpoint(file string) error
+	Resume(file string) error
+	Head() <-chan float64
+
+	// Samples are the samples the sim writes. Copy them with CopyTo
+	// while it runs.
+	Samples() *samples.SamplesCollection
+
+	// StartRun runs epochs, each from a reset, in the background until
+	// StopRun, or the end of an epoch if AutoRunPause is off. PauseRun and
+	// ResumeRun hold it between steps.
+	StartRun() bool
+	PauseRun()
+	ResumeRun()
+	StopRun()
+	RunState() SimState
+
+	// Epochs is how many epochs have run to the end.
+	Epochs() int
+}
+
+// SimState is where a simulation started by StartRun is at.
+type SimState int
+
+const (
+	SimIdle SimState = iota
+	SimRunning
+	SimPaused
+	// Stopped, but still finishing the step it's on.
+	SimStopping
+)
+
+func (s SimState) String() string {
+	switch s {
+	case SimRunning:
+		return "Running"
+	case SimPaused:
+		return "Paused"
+	case SimStopping:
+		return "Stopping"
+	}
+	return "Idle"
 }
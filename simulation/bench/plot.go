@@ -0,0 +1,99 @@
+package bench
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/fogleman/gg"
+)
+
+// Curve colors, one per frequency.
+var curveColors = []color.RGBA{
+	{255, 127, 0, 255},
+	{127, 255, 127, 255},
+	{127, 127, 255, 255},
+	{255, 127, 255, 255},
+	{255, 255, 127, 255},
+}
+
+// Draw plots Δw against Δt, a curve per frequency, onto dc.
+func Draw(dc *gg.Context, points []*Point) {
+	w := float64(dc.Width())
+	h := float64(dc.Height())
+	margin := 40.0
+
+	dc.Identity()
+	dc.SetRGB(0.19, 0.19, 0.19)
+	dc.Clear()
+
+	if len(points) == 0 {
+		return
+	}
+
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	maxY := 0.0
+	for _, p := range points {
+		minX = math.Min(minX, p.Delta)
+		maxX = math.Max(maxX, p.Delta)
+		maxY = math.Max(maxY, math.Abs(p.DeltaW))
+	}
+	if maxX == minX {
+		maxX = minX + 1.0
+	}
+	if maxY == 0.0 {
+		maxY = 1.0
+	}
+
+	// Δw is symmetric about 0 so that LTP and LTD compare.
+	mapX := func(x float64) float64 {
+		return margin + (x-minX)/(maxX-minX)*(w-2*margin)
+	}
+	mapY := func(y float64) float64 {
+		return h/2 - y/maxY*(h/2-margin)
+	}
+
+	dc.SetLineWidth(1.0)
+
+	// Axes through Δt = 0 and Δw = 0.
+	dc.SetRGB(0.55, 0.55, 0.55)
+	dc.DrawLine(margin, mapY(0), w-margin, mapY(0))
+	dc.Stroke()
+	if minX <= 0 && maxX >= 0 {
+		dc.DrawLine(mapX(0), margin, mapX(0), h-margin)
+		dc.Stroke()
+	}
+
+	dc.SetRGB(0.85, 0.85, 0.85)
+	dc.DrawString(fmt.Sprintf("%0.0f", minX), margin, h-margin/2)
+	dc.DrawStringAnchored(fmt.Sprintf("%0.0f ms", maxX), w-margin, h-margin/2, 1, 0)
+	dc.DrawString(fmt.Sprintf("dw %0.4f", maxY), 5, margin/2)
+	dc.DrawString(fmt.Sprintf("%0.4f", -maxY), 5, h-5)
+	dc.DrawStringAnchored("dt = post - pre", w/2, h-margin/2, 0.5, 0)
+
+	// A curve per frequency, in the order run.
+	curve := -1
+	frequency := math.NaN()
+	for _, p := range points {
+		if p.Frequency != frequency {
+			dc.Stroke()
+			curve++
+			frequency = p.Frequency
+
+			c := curveColors[curve%len(curveColors)]
+			dc.SetColor(c)
+			dc.DrawString(fmt.Sprintf("%0.1f Hz", frequency), w-margin-60, margin/2+float64(curve)*15)
+			dc.MoveTo(mapX(p.Delta), mapY(p.DeltaW))
+			continue
+		}
+		dc.LineTo(mapX(p.Delta), mapY(p.DeltaW))
+	}
+	dc.Stroke()
+}
+
+// SavePNG plots the points to a width x height png.
+func SavePNG(fileName string, points []*Point, width, height int) error {
+	dc := gg.NewContext(width, height)
+	Draw(dc, points)
+	return dc.SavePNG(fileName)
+}
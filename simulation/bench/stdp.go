@@ -0,0 +1,266 @@
+package bench
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+
+	"github.com/wdevore/Deuron5/cell"
+	"github.com/wdevore/Deuron5/deuron"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+/*
+The STDP bench measures a ProtoSynapse's learning window. A single
+synapse is driven by pre spikes and the neuron is forced to fire Δt
+after each one. The pairings repeat at a frequency and Δw, the change
+of weight over all the pairings, is measured for each Δt and frequency.
+*/
+
+// Spec describes the pairings, for example:
+//
+//	{
+//		"DeltaMin": -100, "DeltaMax": 100, "DeltaStep": 5,
+//		"Frequencies": [1, 10, 20],
+//		"Pairings": 60,
+//		"Overrides": {"taoP": 20.0}
+//	}
+type Spec struct {
+	// Post minus pre spike times, in milliseconds, from DeltaMin to
+	// DeltaMax by DeltaStep.
+	DeltaMin  float64
+	DeltaMax  float64
+	DeltaStep float64
+
+	// Pairings per second. Each gives a curve.
+	Frequencies []float64
+
+	// Pairings per measurement.
+	Pairings int
+
+	// Weight before the pairings. 0 means weightMax/2, where a Reset
+	// leaves it.
+	Weight float64
+
+	// Synapse and neuron fields applied over the stimulus settings.
+	Overrides map[string]float64
+}
+
+// Point is the weight change for a Δt at a frequency.
+type Point struct {
+	Frequency float64
+	Delta     float64
+
+	W0 float64
+	W  float64
+
+	// W - W0
+	DeltaW float64
+}
+
+// SynapseFields and NeuronFields are the fields ModelOverrides takes
+// from a model.
+var SynapseFields = []string{"ama", "amb", "mu", "lambda", "alpha",
+	"learningRateSlow", "learningRateFast", "taoP", "taoN", "taoI", "distance"}
+var NeuronFields = []string{"nFastSurge", "nSlowSurge", "ntao", "ntaoS", "ntaoJ", "APMax"}
+
+// DefaultSpec is ±100ms in 5ms steps, 60 pairings at 1Hz.
+func DefaultSpec() *Spec {
+	return &Spec{
+		DeltaMin:    -100.0,
+		DeltaMax:    100.0,
+		DeltaStep:   5.0,
+		Frequencies: []float64{1.0},
+		Pairings:    60,
+	}
+}
+
+// LoadSpec reads a spec from a json file. Missing values are defaults.
+func LoadSpec(fileName string) (*Spec, error) {
+	byteValue, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := DefaultSpec()
+	err = json.Unmarshal(byteValue, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return spec, spec.Validate()
+}
+
+// Validate checks the spec makes sense.
+func (sp *Spec) Validate() error {
+	if sp.DeltaStep <= 0 || sp.DeltaMax < sp.DeltaMin {
+		return errors.New("bench: needs DeltaStep > 0 and DeltaMin <= DeltaMax")
+	}
+	if sp.Pairings < 1 {
+		return errors.New("bench: needs at least 1 pairing")
+	}
+	for _, f := range sp.Frequencies {
+		if f <= 0 {
+			return fmt.Errorf("bench: frequency (%f) must be > 0", f)
+		}
+	}
+	return nil
+}
+
+// ModelOverrides returns the synapse and neuron values of a model, for
+// example, as edited in the panels.
+func ModelOverrides(model *deuron.Model) map[string]float64 {
+	m := map[string]float64{}
+	for _, f := range SynapseFields {
+		m[f] = model.GetFloat(f)
+	}
+	for _, f := range NeuronFields {
+		m[f] = model.GetFloat(f)
+	}
+	return m
+}
+
+// Bench runs the pairings of a spec.
+type Bench struct {
+	spec  *Spec
+	model *deuron.Model
+
+	// The "Neuron" of the stimulus settings.
+	settings interface{}
+}
+
+// NewBench creates a bench for the synapse settings of the model's
+// stimulus. The model isn't modified.
+func NewBench(spec *Spec, model *deuron.Model) (*Bench, error) {
+	fileName := "./stimulus/" + model.GetString("Stimulus") + ".json"
+
+	byteValue, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	settingsMap := make(map[string]interface{})
+	err = json.Unmarshal(byteValue, &settingsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	neuron, ok := settingsMap["Neuron"]
+	if !ok {
+		return nil, fmt.Errorf("%s: missing Neuron", fileName)
+	}
+
+	b := new(Bench)
+	b.spec = spec
+	b.model = model
+	b.settings = neuron
+	return b, nil
+}
+
+// Run measures every Δt at every frequency, in frequency order.
+func (b *Bench) Run() []*Point {
+	points := []*Point{}
+
+	for _, f := range b.spec.Frequencies {
+		for delta := b.spec.DeltaMin; delta <= b.spec.DeltaMax+1e-9; delta += b.spec.DeltaStep {
+			points = append(points, b.Pair(f, delta))
+		}
+	}
+
+	return points
+}
+
+// Pair runs the spec's pairings for a single frequency and Δt (ms).
+func (b *Bench) Pair(frequency, delta float64) *Point {
+	// A step is a millisecond.
+	period := int(math.Round(1000.0 / frequency))
+	dt := int(math.Round(delta))
+
+	// Leave room for a post spike before the first pre spike.
+	first := 10
+	if dt < 0 {
+		first -= dt
+	}
+
+	steps := first + (b.spec.Pairings-1)*period + 2
+	if dt > 0 {
+		steps += dt
+	}
+
+	model := b.model.Clone()
+	ctx := deuron.NewContext(model, samples.NewSamplesCollection(1, steps))
+
+	neuron := cell.NewProtoNeuron(ctx).(*cell.ProtoNeuron)
+	den := cell.NewProtoDendrite(neuron, ctx)
+	comp := cell.NewProtoCompartment(den)
+	syn := cell.NewProtoSynapse(comp, cell.Excititory, 0, ctx.DeriveSeed("synapse/0"), ctx)
+	con := cell.NewStraightConnection()
+	syn.Connect(con)
+	neuron.AttachDendrite(den)
+
+	neuron.Load(b.settings)
+	neuron.Reset()
+
+	for key, value := range b.spec.Overrides {
+		sValue := fmt.Sprintf("%f", value)
+		syn.SetField(key, sValue)
+		neuron.SetField(key, sValue)
+	}
+
+	// Only forced spikes.
+	neuron.SetThreshold(math.Inf(1))
+
+	if b.spec.Weight > 0 {
+		syn.SetWeight(b.spec.Weight)
+	}
+
+	w0 := weight(syn)
+
+	for t := 0; t < steps; t++ {
+		k := t - first
+		if k >= 0 && k%period == 0 && k/period < b.spec.Pairings {
+			con.Input(1)
+		}
+
+		k = t - first - dt
+		if k >= 0 && k%period == 0 && k/period < b.spec.Pairings {
+			neuron.Force()
+		}
+
+		neuron.Process()
+		neuron.Integrate(float64(t))
+		con.Post()
+	}
+
+	w := weight(syn)
+
+	return &Point{Frequency: frequency, Delta: float64(dt), W0: w0, W: w, DeltaW: w - w0}
+}
+
+func weight(syn cell.ISynapse) float64 {
+	return syn.ToJSON().(map[string]interface{})["w"].(float64)
+}
+
+// WriteCSV writes the points, one row each.
+func WriteCSV(w io.Writer, points []*Point) {
+	fmt.Fprintln(w, "Frequency,Delta,W0,W,DeltaW")
+	for _, p := range points {
+		fmt.Fprintf(w, "%f,%f,%f,%f,%f\n", p.Frequency, p.Delta, p.W0, p.W, p.DeltaW)
+	}
+}
+
+// SaveCSV writes the points to a file.
+func SaveCSV(fileName string, points []*Point) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	WriteCSV(f, points)
+	return nil
+}
@@ -0,0 +1,108 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+/*
+A protocol runs an experiment as a sequence of timed phases, for example,
+train on pattern A, test with learning frozen and then train on pattern B.
+Rather than changing the Stimulus from the panels by hand each phase says
+what the neuron sees and whether it learns. The samples are marked with
+the phase index (PhaseSamples) so the boundaries can be found afterwards.
+*/
+
+// Protocol is a list of phases, for example:
+//
+//	{
+//		"Name": "train_test",
+//		"Phases": [
+//			{"Name": "train A", "Duration": 5000, "Stimulus": "stim_1", "Learning": true},
+//			{"Name": "test A", "Duration": 1000, "Learning": false},
+//			{"Name": "train B", "Duration": 5000, "Stimulus": "stim_2", "Learning": true,
+//				"Firing_Rate": 0.05, "Overrides": {"threshold": 20.0}}
+//		]
+//	}
+type Protocol struct {
+	Name string
+
+	Phases []*Phase
+}
+
+// Phase is a block of the experiment. Anything missing carries on from the
+// phase before.
+type Phase struct {
+	Name string
+
+	// Milliseconds
+	Duration float64
+
+	// Stimulus switches to stimulus/<Stimulus>, and its settings, at the
+	// start of the phase. The neuron's weights are kept.
+	Stimulus string
+
+	// Poisson noise rate, after the stimulus settings are applied.
+	Firing_Rate *float64
+
+	// false freezes every synapse's weight.
+	Learning *bool
+
+	// Model values, synapse and neuron fields, as a sweep would set them.
+	Overrides map[string]float64
+}
+
+// LoadProtocol reads a protocol from a json file.
+func LoadProtocol(fileName string) (*Protocol, error) {
+	protocolFile, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer protocolFile.Close()
+
+	byteValue, err := ioutil.ReadAll(protocolFile)
+	if err != nil {
+		return nil, err
+	}
+
+	p := new(Protocol)
+	err = json.Unmarshal(byteValue, p)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fileName, err)
+	}
+
+	return p, nil
+}
+
+// Validate checks there is something to run.
+func (p *Protocol) Validate() error {
+	if len(p.Phases) == 0 {
+		return errors.New("protocol has no phases")
+	}
+
+	for i, phase := range p.Phases {
+		if phase.Duration <= 0 {
+			return fmt.Errorf("phase %d (%s) needs a Duration > 0", i, phase.Name)
+		}
+	}
+
+	return nil
+}
+
+// Duration is the total length of the protocol in milliseconds.
+func (p *Protocol) Duration() float64 {
+	d := 0.0
+	for _, phase := range p.Phases {
+		d += phase.Duration
+	}
+	return d
+}
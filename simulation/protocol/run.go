@@ -0,0 +1,95 @@
+package protocol
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/wdevore/Deuron5/deuron"
+	"github.com/wdevore/Deuron5/simulation/runreset"
+	"github.com/wdevore/Deuron5/simulation/sweep"
+)
+
+// Result is the summary of a single phase.
+type Result struct {
+	Phase *Phase
+
+	// Steps [Start, End) of the samples.
+	Start int
+	End   int
+
+	Metrics *sweep.Metrics
+}
+
+// Run runs the protocol on a headless sim from t = 0. The model's Samples
+// is set to the protocol's duration before the sim is created.
+func Run(p *Protocol, model *deuron.Model) (*runreset.RunResetSim, []*Result) {
+	// Sim time ticks in milliseconds, one step each.
+	model.SetFloat("Samples", math.Ceil(p.Duration()/runreset.TimeStep))
+
+	sim := runreset.NewHeadlessRunResetSim(model)
+	sim.Create()
+	sim.Reset()
+
+	results := []*Result{}
+
+	end := 0.0
+	for i, phase := range p.Phases {
+		fmt.Printf("Phase (%d) %s at %0.1f ms\n", i, phase.Name, sim.Time())
+
+		start := sim.Time()
+		begin(sim, i, phase)
+
+		end += phase.Duration
+		sim.RunUntil(end)
+
+		results = append(results, &Result{Phase: phase, Start: int(start), End: int(sim.Time())})
+	}
+
+	sim.PostProcess()
+
+	for _, r := range results {
+		r.Metrics = sweep.CollectMetrics(model, sim.Samples(), r.Start, r.End)
+	}
+
+	return sim, results
+}
+
+// begin applies a phase's changes before its first step.
+func begin(sim *runreset.RunResetSim, index int, phase *Phase) {
+	model := sim.Model()
+
+	sim.SetPhase(index)
+
+	if phase.Stimulus != "" && phase.Stimulus != model.GetString("Stimulus") {
+		sim.SwitchStimulus(phase.Stimulus)
+	}
+
+	if phase.Firing_Rate != nil {
+		sim.SetFiringRate(*phase.Firing_Rate)
+	}
+
+	if phase.Learning != nil {
+		sim.SetLearning(*phase.Learning)
+	}
+
+	for key, value := range phase.Overrides {
+		sim.Override(key, value)
+	}
+}
+
+// WriteCSV writes the results as a table, one row per phase.
+func WriteCSV(w io.Writer, results []*Result) {
+	fmt.Fprintln(w, "Phase,Name,Start,End,OutputRate,RateIn,RateOut,Selectivity,WeightMean,WeightStd,WeightMin,WeightMax")
+
+	for i, r := range results {
+		row := []string{fmt.Sprintf("%d", i), r.Phase.Name, fmt.Sprintf("%d", r.Start), fmt.Sprintf("%d", r.End)}
+		m := r.Metrics
+		for _, v := range []float64{m.OutputRate, m.RateIn, m.RateOut, m.Selectivity,
+			m.WeightMean, m.WeightStd, m.WeightMin, m.WeightMax} {
+			row = append(row, fmt.Sprintf("%f", v))
+		}
+		fmt.Fprintln(w, strings.Join(row, ","))
+	}
+}
@@ -1,8 +1,11 @@
 package runreset
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"strings"
+	"sync"
 
 	"github.com/wdevore/Deuron5/deuron/app/comm"
 
@@ -22,19 +25,55 @@ the neuron maintains and operates within a critical functional region.
 type RunResetSim struct {
 	statusChannel chan string
 
-	stopped bool
-
 	workingPath string
 
 	// Sim ticks at TimeStep(ms) resolution
 	t float64
 
 	sim *Simulation
+
+	model *deuron.Model
+
+	// Headless sims keep their samples and context to themselves rather
+	// than publishing them to the globals used by the graphs.
+	headless bool
+	ctx      *deuron.Context
+
+	// Spikes of every run so far.
+	runs *samples.RunHistory
+
+	// Time of the newest step, for the live graphs. It only holds the
+	// latest so the sim never waits on the GUI.
+	head chan float64
+
+	// The run started by StartRun. While it's paused the steps wait on
+	// cond.
+	control sync.Mutex
+	cond    *sync.Cond
+	state   deuron.SimState
+	epochs  int
+
+	// Events sent while a run is going, applied between its steps.
+	events []*comm.MessageEvent
 }
 
 func NewRunResetSim() deuron.ISimulation {
 	s := new(RunResetSim)
-	s.stopped = true
+	s.model = deuron.SimModel
+	s.runs = samples.Runs
+	s.head = make(chan float64, 1)
+	s.cond = sync.NewCond(&s.control)
+	return s
+}
+
+// NewHeadlessRunResetSim creates a sim that runs without the GUI. It uses
+// its own model and samples which means several can run in parallel.
+func NewHeadlessRunResetSim(model *deuron.Model) *RunResetSim {
+	s := new(RunResetSim)
+	s.model = model
+	s.headless = true
+	s.runs = samples.NewRunHistory(samples.MaxRuns)
+	s.cond = sync.NewCond(&s.control)
 	return s
 }
 
@@ -48,10 +87,9 @@ func (s *RunResetSim) Command(args []string) {
 	// fmt.Printf("Send msg: %s\n", msg)
 	switch args[0] {
 	case "start":
-		s.stopped = false
 		s.Start()
 	case "stop":
-		s.stopped = true
+		s.StopRun()
 	case "ping":
 		go s.respond("pong")
 	}
@@ -67,52 +105,50 @@ func (s *RunResetSim) respond(msg string) {
 	s.statusChannel <- msg
 }
 
+// Start creates the sim and runs it, see StartRun.
 func (s *RunResetSim) Start() {
+	// Creating again would change the sim under a run.
+	if s.RunState() != deuron.SimIdle {
+		fmt.Println("The sim is already running.")
+		return
+	}
+
 	s.Create()
 	fmt.Println("Starting...")
 
-	// Start the simulation loop in a coroutine.
-	go s.run()
-	deuron.SimModel.SetString("Status", "Running...")
+	if s.StartRun() {
+		s.model.SetString("Status", "Running...")
+	}
 }
 
 func (s *RunResetSim) Create() {
 	fmt.Println("Creating...")
 	s.t = 0.0
 
-	s.sim = NewSimulation(s.statusChannel)
+	synCnt := int(s.model.GetFloat("Synapse_Count"))
 
-	// Setup the neuron while connecting the noise and stimulus.
-	synCnt := s.sim.initialize()
-
-	duration := deuron.SimModel.GetFloat("Samples")
+	duration := s.model.GetFloat("Samples")
 
 	sampleSize := int(duration)
 
 	fmt.Printf("Syn cnt: %d, duration: %d\n", synCnt, sampleSize)
 
 	// The samples is where we collect all the data.
-	samples.Sim = samples.NewSamplesCollection(synCnt, sampleSize)
-
-	fmt.Println("Created.")
-}
+	sams := samples.NewSamplesCollection(synCnt, sampleSize)
+	if s.headless {
+		s.ctx = deuron.NewContext(s.model, sams)
+	} else {
+		// The graphs draw a copy, the GUI copies what's been written.
+		samples.Sim = samples.NewSamplesCollection(synCnt, sampleSize)
+		s.ctx = deuron.DefaultContext(sams)
+	}
 
-// This runs in a "Go"routine.
-func (s *RunResetSim) run() {
-	fmt.Println("RunReset: run() loop begining")
-	// Run the sim for a fixed amount of time and then reset.
-	duration := deuron.SimModel.GetFloat("Samples")
+	s.sim = NewSimulation(s.statusChannel, s.ctx)
 
-	for !s.stopped {
-		if s.t >= duration {
-			s.Reset()
-		} else {
-			s.Step()
-		}
-	}
+	// Setup the neuron while connecting the noise and stimulus.
+	s.sim.initialize()
 
-	fmt.Println("RunReset: run() loop exited")
-	s.respond("Stopped")
+	fmt.Println("Created.")
 }
 
 func (s *RunResetSim) Reset() {
@@ -121,29 +157,330 @@ func (s *RunResetSim) Reset() {
 
 	// Reset random seeds.
 	s.sim.reset()
+
+	// Bounds widen from the new run's samples, not the last run's.
+	s.ctx.Samples.Lock()
+	s.ctx.Samples.ResetBounds()
+	s.ctx.Samples.Unlock()
 }
 
 func (s *RunResetSim) Step() {
+	// The samples aren't copied while the step writes them.
+	s.ctx.Samples.Lock()
 	s.sim.simulate(s.t)
+	s.ctx.Samples.Unlock()
+
+	s.publish(s.t)
 	s.t += TimeStep
 }
 
+// publish replaces any time the GUI hasn't read yet with t.
+func (s *RunResetSim) publish(t float64) {
+	// Headless simulations don't have anyone listening.
+	if s.head == nil {
+		return
+	}
+
+	select {
+	case s.head <- t:
+	default:
+		// Only this goroutine sends so, once drained, there's room.
+		select {
+		case <-s.head:
+		default:
+		}
+		s.head <- t
+	}
+}
+
 // This can run in a goroutine or not.
 func (s *RunResetSim) RunPause() {
 	s.Reset()
-	duration := deuron.SimModel.GetFloat("Samples")
+	s.Continue()
+}
+
+// StartRun runs epochs in a goroutine, see deuron.ISimulation. It returns
+// false if a run is already going.
+func (s *RunResetSim) StartRun() bool {
+	s.control.Lock()
+	defer s.control.Unlock()
+
+	// Create makes the sim.
+	if s.state != deuron.SimIdle || s.sim == nil {
+		return false
+	}
+	s.state = deuron.SimRunning
+
+	go s.runEpochs()
 
+	return true
+}
+
+func (s *RunResetSim) runEpochs() {
 	fmt.Println("Starting run...")
+	for s.runEpoch() && s.autoRun() {
+		fmt.Printf("Epoch %d complete.\n", s.Epochs())
+	}
+	fmt.Println("Run complete.")
+
+	// Events sent while it was stopping.
+	s.applyEvents()
+
+	s.setState(deuron.SimIdle)
+}
+
+// runEpoch runs from a reset to the end of the run. It returns false if
+// the run was stopped part way.
+func (s *RunResetSim) runEpoch() bool {
+	s.Reset()
+
+	duration := s.model.GetFloat("Samples")
+
 	for s.t < duration {
-		// Run
+		// What ran of a stopped epoch is still shown but isn't post
+		// processed, the samples past t haven't been written.
+		if !s.proceed() {
+			return false
+		}
+		s.Step()
+	}
+
+	s.sim.PostProcess()
+	s.runs.Add(s.ctx.Samples)
+
+	s.control.Lock()
+	s.epochs++
+	s.control.Unlock()
+
+	return true
+}
+
+// autoRun is true if another epoch follows the one that ended.
+func (s *RunResetSim) autoRun() bool {
+	s.control.Lock()
+	defer s.control.Unlock()
+
+	return s.state != deuron.SimStopping && s.model.GetFloat("AutoRunPause") == 1
+}
+
+// proceed applies the events sent since the last step and waits while the
+// run is paused. It's false once it's stopping.
+func (s *RunResetSim) proceed() bool {
+	s.applyEvents()
+
+	s.control.Lock()
+	defer s.control.Unlock()
+
+	for s.state == deuron.SimPaused {
+		s.cond.Wait()
+
+		// Edits made while paused apply straight away.
+		if len(s.events) > 0 {
+			s.control.Unlock()
+			s.applyEvents()
+			s.control.Lock()
+		}
+	}
+
+	return s.state == deuron.SimRunning
+}
+
+// applyEvents applies the events queued by SendEvent, on the run's
+// goroutine so they don't change the sim part way through a step.
+func (s *RunResetSim) applyEvents() {
+	s.control.Lock()
+	events := s.events
+	s.events = nil
+	s.control.Unlock()
+
+	for _, event := range events {
+		s.sim.SendEvent(event)
+	}
+}
+
+func (s *RunResetSim) setState(state deuron.SimState) {
+	s.control.Lock()
+	defer s.control.Unlock()
+
+	s.state = state
+	s.cond.Broadcast()
+}
+
+// PauseRun holds a running run before its next step.
+func (s *RunResetSim) PauseRun() {
+	s.control.Lock()
+	defer s.control.Unlock()
+
+	if s.state == deuron.SimRunning {
+		s.state = deuron.SimPaused
+	}
+}
+
+// ResumeRun carries on with a paused run.
+func (s *RunResetSim) ResumeRun() {
+	s.control.Lock()
+	defer s.control.Unlock()
+
+	if s.state == deuron.SimPaused {
+		s.state = deuron.SimRunning
+		s.cond.Broadcast()
+	}
+}
+
+// StopRun ends a run, paused or not, after the step it's on.
+func (s *RunResetSim) StopRun() {
+	s.control.Lock()
+	defer s.control.Unlock()
+
+	if s.state == deuron.SimRunning || s.state == deuron.SimPaused {
+		s.state = deuron.SimStopping
+		s.cond.Broadcast()
+	}
+}
+
+// RunState is where the run started by StartRun is at.
+func (s *RunResetSim) RunState() deuron.SimState {
+	s.control.Lock()
+	defer s.control.Unlock()
+
+	return s.state
+}
+
+// Epochs is how many epochs StartRun has run to the end.
+func (s *RunResetSim) Epochs() int {
+	s.control.Lock()
+	defer s.control.Unlock()
+
+	return s.epochs
+}
+
+// RunUntil steps the sim up to, but not including, time t. It doesn't
+// reset first which means it can follow a Reset or a Resume.
+func (s *RunResetSim) RunUntil(t float64) {
+	duration := s.model.GetFloat("Samples")
+
+	for s.t < t && s.t < duration {
 		s.Step()
 	}
+}
+
+// Continue runs from the current time to the end of the run.
+func (s *RunResetSim) Continue() {
+	fmt.Println("Starting run...")
+	s.RunUntil(s.model.GetFloat("Samples"))
 	fmt.Println("Run complete.")
 
 	s.sim.PostProcess()
+
+	s.runs.Add(s.ctx.Samples)
+}
+
+// Checkpoint writes the complete state at the current time: the model,
+// samples so far, every trace, stream position and RNG. Resume picks it
+// up again, for example, after a restart or to branch an experiment.
+func (s *RunResetSim) Checkpoint(file string) error {
+	cp := map[string]interface{}{
+		"t":          s.t,
+		"Model":      s.model.ToJSON(),
+		"Seeds":      s.ctx.SeedReport(),
+		"Samples":    s.ctx.Samples.State(int(s.t)),
+		"Simulation": s.sim.State(),
+	}
+
+	jsonString, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Writing checkpoint to (%s)\n", file)
+
+	return ioutil.WriteFile(file, jsonString, 0644)
 }
 
+// Resume creates the sim from a checkpoint. Continue then carries on
+// exactly as the original run would have.
+func (s *RunResetSim) Resume(file string) error {
+	byteValue, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	cp := make(map[string]interface{})
+	err = json.Unmarshal(byteValue, &cp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Resuming from checkpoint (%s)\n", file)
+
+	s.model.Load(cp["Model"])
+
+	s.Create()
+
+	// Create loads the stimulus settings over the top of the model.
+	s.model.Load(cp["Model"])
+
+	s.ctx.Samples.RestoreState(cp["Samples"])
+	s.sim.RestoreState(cp["Simulation"])
+
+	s.t = cp["t"].(float64)
+
+	return nil
+}
+
+// PostProcess finishes the samples of a run made with RunUntil.
+func (s *RunResetSim) PostProcess() {
+	s.sim.PostProcess()
+}
+
+// Time is the current sim time in milliseconds.
+func (s *RunResetSim) Time() float64 {
+	return s.t
+}
+
+// SetPhase marks the samples from now on with a protocol phase.
+func (s *RunResetSim) SetPhase(phase int) {
+	s.sim.phase = phase
+}
+
+// SwitchStimulus changes the stimulus, and its settings, without
+// reloading the neuron.
+func (s *RunResetSim) SwitchStimulus(name string) {
+	s.sim.switchStimulus(name)
+}
+
+// SetFiringRate changes the poisson noise rate without reseeding.
+func (s *RunResetSim) SetFiringRate(rate float64) {
+	s.sim.setFiringRate(rate)
+}
+
+// SetLearning turns weight changes on or off.
+func (s *RunResetSim) SetLearning(on bool) {
+	s.sim.setLearning(on)
+}
+
+// Override sets a model property and passes it on to the synapses and
+// the neuron, each of which ignores fields that aren't theirs.
+func (s *RunResetSim) Override(key string, value float64) {
+	s.model.SetFloat(key, value)
+
+	sValue := fmt.Sprintf("%f", value)
+	s.SendEvent(&comm.MessageEvent{Target: "Data", Action: "Changed", Message: "Synapse", Field: key, Value: sValue})
+	s.SendEvent(&comm.MessageEvent{Target: "Data", Action: "Changed", Message: "Neuron", Field: key, Value: sValue})
+}
+
+// SendEvent passes an event, for example a panel edit, to the sim. While a
+// run is going it's queued for the run to apply between steps.
 func (s *RunResetSim) SendEvent(event *comm.MessageEvent) {
+	s.control.Lock()
+	if s.state != deuron.SimIdle {
+		s.events = append(s.events, event)
+		s.cond.Broadcast()
+		s.control.Unlock()
+		return
+	}
+	s.control.Unlock()
+
 	s.sim.SendEvent(event)
 }
 
@@ -154,3 +491,27 @@ func (s *RunResetSim) ToJSON() interface{} {
 func (s *RunResetSim) Load(json interface{}) {
 	s.sim.Load(json)
 }
+
+// Samples returns the samples collected by the last run.
+func (s *RunResetSim) Samples() *samples.SamplesCollection {
+	return s.ctx.Samples
+}
+
+// Head delivers the time of the newest step while the sim runs.
+func (s *RunResetSim) Head() <-chan float64 {
+	return s.head
+}
+
+// Runs returns the spikes of every completed run.
+func (s *RunResetSim) Runs() *samples.RunHistory {
+	return s.runs
+}
+
+// Context returns the context created by Create.
+func (s *RunResetSim) Context() *deuron.Context {
+	return s.ctx
+}
+
+func (s *RunResetSim) Model() *deuron.Model {
+	return s.model
+}
@@ -1,11 +1,9 @@
 package runreset
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"math/rand"
 	"os"
 	"strconv"
 
@@ -15,7 +13,6 @@ import (
 	"github.com/wdevore/Deuron5/cell"
 	"github.com/wdevore/Deuron5/cell/stimulus"
 	"github.com/wdevore/Deuron5/deuron"
-	"github.com/wdevore/Deuron5/simulation/samples"
 )
 
 type Simulation struct {
@@ -23,50 +20,61 @@ type Simulation struct {
 
 	neuron cell.ICell
 
-	poiStreams  *sll.List
-	stimStreams *sll.List
-	syns        *sll.List
-	cons        *sll.List
+	poiStreams *sll.List
+	syns       *sll.List
+	cons       *sll.List
 
-	pattern1 *stimulus.PoissonPatternStream
+	// One presenter per pattern of the stimulus file.
+	presenters []stimulus.IPatternPresenter
+
+	// Per synapse OR of the stimulus lanes, for samples.
+	stimOutputs []int
 
 	cnt int
 
+	// Current protocol phase, for samples.
+	phase int
+
 	settingsMap map[string]interface{}
+
+	// Optional time varying noise rates from the settings.
+	noise *stimulus.NoiseSpec
+	// Recorded and correlated noise keyed by synapse.
+	noiseLanes map[int]stimulus.IPatternStream
+
+	ctx *deuron.Context
 }
 
 // NewSimulation creates a simulation
-func NewSimulation(channel chan string) *Simulation {
+func NewSimulation(channel chan string, ctx *deuron.Context) *Simulation {
 	s := new(Simulation)
 	s.channel = channel
+	s.ctx = ctx
 	return s
 }
 
-var ran = rand.New(rand.NewSource(1963))
-
 func (s *Simulation) initialize() int {
 	// The single neuron being simulated.
-	s.neuron = cell.NewProtoNeuron()
+	s.neuron = cell.NewProtoNeuron(s.ctx)
 
-	threshold := deuron.SimModel.GetFloat("threshold")
+	threshold := s.ctx.Model.GetFloat("threshold")
 	s.neuron.SetThreshold(threshold)
 
 	// A neuron has a dendrite
-	den := cell.NewProtoDendrite(s.neuron)
+	den := cell.NewProtoDendrite(s.neuron, s.ctx)
 
 	// A dendrite has 1 or more compartments--one in this simulation.
 	comp := cell.NewProtoCompartment(den)
 
 	// Create 80% Excite and 20% Inhibit
 	// The streams are setup with N channels.
-	synCount := int(deuron.SimModel.GetFloat("Synapse_Count"))
+	synCount := int(s.ctx.Model.GetFloat("Synapse_Count"))
 
 	excite := int(float64(synCount) * 0.8)
 	inhibit := int(float64(synCount) * 0.2)
 
 	// Collections used for convenience of iteration.
 	s.poiStreams = sll.New()
-	s.stimStreams = sll.New()
 	s.syns = sll.New()
 	s.cons = sll.New()
 
@@ -76,10 +84,7 @@ func (s *Simulation) initialize() int {
 
 	s.loadSettings()
 
-	// Now fill streams with patterns
-	s.createPatterns()
-
-	s.pattern1.Begin()
+	s.createNoiseLanes(excite + inhibit)
 
 	// Begin construction of the neuron.
 	// For each synapse we attach a connection.
@@ -87,7 +92,7 @@ func (s *Simulation) initialize() int {
 	// a poisson-noise stream and pattern stream.
 	for i := 0; i < excite; i++ {
 		// Create a synapse with a new id and associate it with a compartment and mark it as excititory.
-		syn := cell.NewProtoSynapse(comp, cell.Excititory, synID, ran.Int63())
+		syn := cell.NewProtoSynapse(comp, cell.Excititory, synID, s.ctx.DeriveSeed(fmt.Sprintf("synapse/%d", synID)), s.ctx)
 
 		// Collect it for iteration during simulation.
 		s.syns.Add(syn)
@@ -97,9 +102,7 @@ func (s *Simulation) initialize() int {
 		s.cons.Add(con)
 
 		// Create a poisson noise stream that will feed into the connection
-		seed := ran.Int63()
-		poi := stimulus.NewPoissonStream(seed).(*stimulus.PoissonStream)
-		poi.SetId(poiID)
+		poi := s.createNoise(poiID)
 
 		// Collect streams so we can iterate them later.
 		s.poiStreams.Add(poi)
@@ -110,14 +113,6 @@ func (s *Simulation) initialize() int {
 		// -----------------------------------------------------------------
 		poi.Attach(con) // route noise stream into connection
 
-		// Get the stream driving the pattern
-		stream := s.pattern1.Stream()
-		// Collect it for easy iteration.
-		s.stimStreams.Add(stream)
-
-		// route pattern stream into connection as well.
-		stream.Attach(con)
-
 		// Finally route connection to synapse
 		syn.Connect(con)
 
@@ -128,30 +123,27 @@ func (s *Simulation) initialize() int {
 
 	// Repeat for inhibition.
 	for i := 0; i < inhibit; i++ {
-		syn := cell.NewProtoSynapse(comp, cell.Inhibitory, synID, ran.Int63())
+		syn := cell.NewProtoSynapse(comp, cell.Inhibitory, synID, s.ctx.DeriveSeed(fmt.Sprintf("synapse/%d", synID)), s.ctx)
 		s.syns.Add(syn)
 
 		con := cell.NewStraightConnection()
 		s.cons.Add(con)
 
-		seed := ran.Int63()
-		poi := stimulus.NewPoissonStream(seed).(stimulus.IPatternStream)
-		poi.SetId(poiID)
+		poi := s.createNoise(poiID)
 
 		s.poiStreams.Add(poi)
 		// Connect stream to input of connection
 		poi.Attach(con)
 
-		stim := s.pattern1.Stream()
-		s.stimStreams.Add(stim)
-		stim.Attach(con) // route stimulus into connection
-
 		syn.Connect(con) // attach connection into synapse
 
 		synID++
 		poiID++
 	}
 
+	// Now fill streams with patterns and route them into the connections.
+	s.createPatterns()
+
 	// Now we can attach dendrite to neuron. Note, neurons can have more than one dendrite.
 	s.neuron.AttachDendrite(den)
 
@@ -174,11 +166,14 @@ func (s *Simulation) reset() {
 	}
 
 	// Reset stimulus
-	s.pattern1.Reset()
+	for _, presenter := range s.presenters {
+		presenter.Reset()
+	}
 
 	// Reset neurons
 	s.neuron.Reset()
 	s.cnt = 0
+	s.phase = 0
 }
 
 // A single pass of a simulation.
@@ -194,10 +189,6 @@ func (s *Simulation) simulate(t float64) {
 	s.diagnostics(t) // Collect samples for inspection
 
 	s.post()
-
-	// Send message back to App.pollForMessage
-	msg := fmt.Sprintf("(%0.1f)", t)
-	s.respond(msg)
 }
 
 func (s *Simulation) pre() {
@@ -209,7 +200,9 @@ func (s *Simulation) pre() {
 	}
 
 	// Step all the stimulus streams
-	s.pattern1.Step()
+	for _, presenter := range s.presenters {
+		presenter.Step()
+	}
 }
 
 func (s *Simulation) diagnostics(t float64) {
@@ -219,24 +212,38 @@ func (s *Simulation) diagnostics(t float64) {
 	it := s.poiStreams.Iterator()
 	for it.Next() {
 		pois := it.Value().(stimulus.IPatternStream)
-		samples.Sim.PoiSamples.Put(t, pois.Output(), pois.Id(), 3)
-	}
-
-	if s.pattern1.Begin() {
-		more := true
-		for more {
-			stim := s.pattern1.Stream()
-			if stim == nil {
-				more = false
-			} else {
-				samples.Sim.StimSamples.Put(t, stim.Output(), stim.Id(), 4)
-				more = s.pattern1.Next()
+		s.ctx.Samples.PoiSamples.Put(t, pois.Output(), pois.Id(), 3)
+	}
+
+	// Several lanes may feed the same synapse.
+	for i := range s.stimOutputs {
+		s.stimOutputs[i] = 0
+	}
+
+	presenting := 0.0
+	for _, presenter := range s.presenters {
+		for more := presenter.Begin(); more; more = presenter.Next() {
+			stim := presenter.Stream()
+			if stim.Id() >= 0 && stim.Id() < len(s.stimOutputs) {
+				s.stimOutputs[stim.Id()] |= stim.Output()
 			}
 		}
+
+		if presenter.IsPresenting() {
+			presenting = 1.0
+		}
+	}
+
+	for id, output := range s.stimOutputs {
+		s.ctx.Samples.StimSamples.Put(t, output, id, 4)
 	}
 
 	// Capture the cell's current output
-	samples.Sim.CellSamples.Put(t, float64(s.neuron.Output()), s.neuron.ID(), 0)
+	s.ctx.Samples.CellSamples.Put(t, float64(s.neuron.Output()), s.neuron.ID(), 0)
+
+	s.ctx.Samples.PatternSamples.Put(t, presenting, 0, 0)
+
+	s.ctx.Samples.PhaseSamples.Put(t, float64(s.phase), 0, 0)
 }
 
 func (s *Simulation) Load(json interface{}) {
@@ -244,6 +251,59 @@ func (s *Simulation) Load(json interface{}) {
 	s.neuron.Load(json)
 }
 
+// State captures the neuron, streams and pattern for a checkpoint.
+func (s *Simulation) State() interface{} {
+	pois := make([]interface{}, s.poiStreams.Size())
+
+	it := s.poiStreams.Iterator()
+	ind := 0
+	for it.Next() {
+		poi := it.Value().(stimulus.IPatternStream)
+		pois[ind] = poi.State()
+		ind++
+	}
+
+	patterns := make([]interface{}, len(s.presenters))
+	for i, presenter := range s.presenters {
+		patterns[i] = presenter.State()
+	}
+
+	m := map[string]interface{}{
+		"cnt":      s.cnt,
+		"phase":    s.phase,
+		"Neuron":   s.neuron.State(),
+		"Poisson":  pois,
+		"Patterns": patterns,
+	}
+
+	return m
+}
+
+func (s *Simulation) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
+
+	s.cnt = int(jmap["cnt"].(float64))
+	if phase, ok := jmap["phase"]; ok {
+		s.phase = int(phase.(float64))
+	}
+
+	s.neuron.RestoreState(jmap["Neuron"])
+
+	pois := jmap["Poisson"].([]interface{})
+	it := s.poiStreams.Iterator()
+	i := 0
+	for it.Next() {
+		poi := it.Value().(stimulus.IPatternStream)
+		poi.RestoreState(pois[i])
+		i++
+	}
+
+	patterns := jmap["Patterns"].([]interface{})
+	for i, presenter := range s.presenters {
+		presenter.RestoreState(patterns[i])
+	}
+}
+
 // Post process for a single pass
 func (s *Simulation) post() {
 	// Post is a preperation for next pass.
@@ -264,10 +324,17 @@ func (s *Simulation) PostProcess() {
 
 	// Post process any samples.
 	// fmt.Println("Post processing...")
-	samples.Sim.Post()
+	s.ctx.Samples.Lock()
+	s.ctx.Samples.Post()
+	s.ctx.Samples.Unlock()
 }
 
 func (s *Simulation) respond(msg string) {
+	// Headless simulations don't have anyone listening.
+	if s.channel == nil {
+		return
+	}
+
 	// Send message back to the App
 	s.channel <- msg
 }
@@ -287,8 +354,8 @@ func (s *Simulation) SendEvent(event *comm.MessageEvent) {
 					break
 				case "Stimulus":
 					// Changing stimulus
-					expandFactor := int(deuron.SimModel.GetFloat("StimulusScaler"))
-					s.loadPatterns(expandFactor)
+					scaler := s.ctx.Model.GetFloat("StimulusScaler")
+					s.loadPatterns(scaler)
 					s.loadSettings()
 					model := s.settingsMap["Neuron"]
 					s.Load(model)
@@ -306,7 +373,7 @@ func (s *Simulation) SendEvent(event *comm.MessageEvent) {
 			case "Neuron":
 				s.neuron.SetField(event.Field, event.Value)
 
-				threshold := deuron.SimModel.GetFloat("threshold")
+				threshold := s.ctx.Model.GetFloat("threshold")
 				s.neuron.SetThreshold(threshold)
 				break
 			}
@@ -316,67 +383,129 @@ func (s *Simulation) SendEvent(event *comm.MessageEvent) {
 	}
 }
 
-func (s *Simulation) createPatterns() {
-	// ------------------------------------------------------------
-	// Create collection
-	s.pattern1 = stimulus.NewPoissonPatternStream(123)
-	// s.pattern1.Period(100, 25) // Pattern will be applied at 30Hz or every 33ms
+// createNoise creates the noise stream for a synapse. The stimulus
+// settings may give it a time varying rate, otherwise it fires at the
+// model's Firing_Rate.
+func (s *Simulation) createNoise(id int) stimulus.IPatternStream {
+	seed := s.ctx.DeriveSeed(fmt.Sprintf("poisson/%d", id))
 
-	expandFactor := int(deuron.SimModel.GetFloat("StimulusScaler"))
+	poi := s.createRateNoise(id, seed)
+	if poi == nil {
+		poi = stimulus.NewPoissonStream(seed, s.ctx)
+	}
 
-	s.loadPatterns(expandFactor)
+	poi.SetId(id)
+
+	return poi
 }
 
-func (s *Simulation) loadPatterns(expandFactor int) {
-	// Load stimulus patterns
-	patFile := "./stimulus/" + deuron.SimModel.GetString("Stimulus") + ".txt"
+// createNoiseLanes builds the settings' correlated groups and recordings
+// for synCnt synapses.
+func (s *Simulation) createNoiseLanes(synCnt int) {
+	s.noiseLanes = map[int]stimulus.IPatternStream{}
 
-	patternsFile, err := os.Open(patFile)
-	if err != nil {
-		fmt.Println(err)
+	if s.noise == nil {
 		return
 	}
 
-	fmt.Printf("Opened stimulus (%s)\n", patFile)
+	// TimeStep is in microseconds.
+	dt := s.ctx.Model.GetFloat("TimeStep") / 1000.0
 
-	defer patternsFile.Close()
+	for i, group := range s.noise.Groups {
+		lanes, err := group.Build(s.ctx.DeriveSeed(fmt.Sprintf("correlated/%d", i)), dt, synCnt)
+		if err != nil {
+			fmt.Printf("Correlated group (%d): %v\n", i, err)
+			continue
+		}
+		for syn, lane := range lanes {
+			s.noiseLanes[syn] = lane
+		}
+	}
 
-	scanner := bufio.NewScanner(patternsFile)
-	// spk.SetSpikes([]int{0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 1, 0, 0, 1, 0, 0, 1, 0, 0, 0, 1, 0, 0})
+	for i, playback := range s.noise.Playback {
+		lanes, err := playback.Build("./stimulus/", dt, synCnt)
+		if err != nil {
+			fmt.Printf("Playback (%d): %v\n", i, err)
+			continue
+		}
+		for syn, lane := range lanes {
+			s.noiseLanes[syn] = lane
+		}
+	}
+}
 
-	s.pattern1.Reset()
+func (s *Simulation) createRateNoise(id int, seed int64) stimulus.IPatternStream {
+	if lane, ok := s.noiseLanes[id]; ok {
+		return lane
+	}
 
-	if s.stimStreams.Empty() {
-		ind := 0
-		for scanner.Scan() {
-			spk := stimulus.NewSpikeStream().(*stimulus.SpikeStream)
+	// The settings may not have loaded.
+	if s.noise == nil {
+		return nil
+	}
 
-			spk.SetId(ind)
-			strm := scanner.Text()
-			spk.SetSpikesFromString(strm)
-			// fmt.Printf("(%d) %s\n", ind, strm)
-			spk.Expand(expandFactor)
-			s.pattern1.Add(spk)
-			ind++
+	spec := s.noise.RateFor(id)
+	if spec == nil {
+		return nil
+	}
 
-			s.stimStreams.Add(spk)
-		}
-	} else {
-		it := s.stimStreams.Iterator()
-
-		for scanner.Scan() {
-			if it.Next() {
-				stream := it.Value().(*stimulus.SpikeStream)
-				stream.SetSpikesFromString(scanner.Text())
-				stream.Expand(expandFactor)
+	dt := s.ctx.Model.GetFloat("TimeStep") / 1000.0
+
+	poi, err := spec.NewStream(seed, "./stimulus/", dt)
+	if err != nil {
+		fmt.Printf("Noise for synapse (%d): %v\n", id, err)
+		return nil
+	}
+
+	return poi
+}
+
+// createPatterns builds a presenter for each pattern of the stimulus.
+// Each pattern is presented periodically, at poisson ISIs or on a
+// schedule as the stimulus file and the model's Hertz say.
+func (s *Simulation) createPatterns() {
+	scaler := s.ctx.Model.GetFloat("StimulusScaler")
+
+	s.loadPatterns(scaler)
+}
+
+// loadPatterns (re)builds the presenters from the stimulus file and routes
+// each lane into the connection of the synapse it names.
+func (s *Simulation) loadPatterns(scaler float64) {
+	sf, err := stimulus.LoadStimulus("./stimulus/", s.ctx.Model.GetString("Stimulus"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	synCnt := s.cons.Size()
+
+	s.presenters, err = sf.Build(s.ctx, "./stimulus/", scaler)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	s.stimOutputs = make([]int, synCnt)
+
+	for _, presenter := range s.presenters {
+		for more := presenter.Begin(); more; more = presenter.Next() {
+			stream := presenter.Stream()
+			con, ok := s.cons.Get(stream.Id())
+			if !ok {
+				fmt.Printf("Stimulus lane for synapse (%d) ignored, there are only %d synapses\n", stream.Id(), synCnt)
+				continue
 			}
+			stream.Attach(con.(cell.IConnection))
 		}
+
+		presenter.Reset()
 	}
 }
 
 func (s *Simulation) loadSettings() {
 	// Load stimulus patterns
-	fileName := "./stimulus/" + deuron.SimModel.GetString("Stimulus") + ".json"
+	fileName := "./stimulus/" + s.ctx.Model.GetString("Stimulus") + ".json"
 
 	settingsFile, err := os.Open(fileName)
 	if err != nil {
@@ -399,7 +528,7 @@ func (s *Simulation) loadSettings() {
 		return
 	}
 
-	m := deuron.SimModel
+	m := s.ctx.Model
 
 	m.SetFloat("StimulusScaler", s.settingsMap["StimulusScaler"].(float64))
 	m.SetFloat("Hertz", s.settingsMap["Hertz"].(float64))
@@ -409,14 +538,59 @@ func (s *Simulation) loadSettings() {
 	m.SetFloat("Poisson_Pattern_spread", s.settingsMap["Poisson_Pattern_spread"].(float64))
 	m.SetFloat("Poisson_Pattern_min", s.settingsMap["Poisson_Pattern_min"].(float64))
 
+	s.noise, err = stimulus.ParseNoiseSpec(s.settingsMap["Noise"])
+	if err != nil {
+		fmt.Println(err)
+	}
+
+}
+
+// switchStimulus changes to another stimulus part way through a run. Unlike
+// the Stimulus event the neuron, and what it has learnt, is kept.
+func (s *Simulation) switchStimulus(name string) {
+	s.ctx.Model.SetString("Stimulus", name)
+
+	s.loadSettings()
+	s.loadPatterns(s.ctx.Model.GetFloat("StimulusScaler"))
+
+	s.setFiringRate(s.ctx.Model.GetFloat("Firing_Rate"))
+}
+
+// setFiringRate changes the rate of the plain poisson noise. Rate curves,
+// correlated groups and recordings keep their own rates.
+func (s *Simulation) setFiringRate(rate float64) {
+	s.ctx.Model.SetFloat("Firing_Rate", rate)
+
+	it := s.poiStreams.Iterator()
+	for it.Next() {
+		if poi, ok := it.Value().(*stimulus.PoissonStream); ok {
+			poi.SetFiringRate(rate)
+		}
+	}
+}
+
+// setLearning freezes, or unfreezes, every synapse's weight.
+func (s *Simulation) setLearning(on bool) {
+	value := "0"
+	if on {
+		value = "1"
+	}
+
+	it := s.syns.Iterator()
+	for it.Next() {
+		synapse := it.Value().(cell.ISynapse)
+		synapse.SetField("learning", value)
+	}
 }
 
 func (s *Simulation) ExpandStreams(scaler float64) {
-	s.pattern1.ExpandStreams(scaler)
+	for _, presenter := range s.presenters {
+		presenter.ExpandStreams(scaler)
+	}
 }
 
 func (s *Simulation) ToJSON() interface{} {
-	mo := deuron.SimModel
+	mo := s.ctx.Model
 
 	m := map[string]interface{}{
 		"Firing_Rate":            mo.GetFloat("Firing_Rate"),
@@ -431,6 +605,9 @@ func (s *Simulation) ToJSON() interface{} {
 		"Hertz":          mo.GetFloat("Hertz"),
 
 		"Neuron": s.neuron.ToJSON(),
+
+		// How this run's randomness was seeded.
+		"Seeds": s.ctx.SeedReport(),
 	}
 
 	return m
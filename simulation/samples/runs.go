@@ -0,0 +1,141 @@
+package samples
+
+import (
+	"math"
+	"sync"
+)
+
+// MaxRuns is how many runs a RunHistory keeps, the oldest are dropped.
+const MaxRuns = 100
+
+// Runs holds the runs of the app's sim. Unlike Sim it survives the
+// samples being recreated.
+var Runs = NewRunHistory(MaxRuns)
+
+// Run is what's kept of a single run: when the neuron fired and when each
+// pattern presentation began, in milliseconds.
+type Run struct {
+	Duration int
+	Spikes   []float64
+	Onsets   []float64
+}
+
+// RunHistory keeps the output spike train of successive runs, for
+// example, for a raster plot or a PSTH.
+// It's added to by the sim while the GUI reads it.
+type RunHistory struct {
+	runs []*Run
+	max  int
+
+	mutex sync.Mutex
+}
+
+func NewRunHistory(max int) *RunHistory {
+	h := new(RunHistory)
+	h.max = max
+	return h
+}
+
+// Add records the cell's spikes and the pattern onsets of a run.
+func (h *RunHistory) Add(sc *SamplesCollection) {
+	r := new(Run)
+	r.Duration = sc.CellSamples.Size()
+
+	if l, ok := sc.CellSamples.GetLanes().Get(0); ok {
+		for _, s := range l.(*SamplesLane).Values {
+			if v, ok := s.Value.(float64); ok && v > 0 {
+				r.Spikes = append(r.Spikes, s.Time)
+			}
+		}
+	}
+
+	if l, ok := sc.PatternSamples.GetLanes().Get(0); ok {
+		presenting := false
+		for _, s := range l.(*SamplesLane).Values {
+			v, _ := s.Value.(float64)
+			if v > 0 && !presenting {
+				r.Onsets = append(r.Onsets, s.Time)
+			}
+			presenting = v > 0
+		}
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.runs = append(h.runs, r)
+	if len(h.runs) > h.max {
+		h.runs = h.runs[len(h.runs)-h.max:]
+	}
+}
+
+// Runs are oldest first.
+func (h *RunHistory) Runs() []*Run {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return append([]*Run{}, h.runs...)
+}
+
+func (h *RunHistory) Clear() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.runs = nil
+}
+
+// Interval is the shortest time between pattern onsets of any run, 0 if
+// no run has two onsets.
+func (h *RunHistory) Interval() float64 {
+	interval := math.Inf(1)
+	for _, r := range h.Runs() {
+		for i := 1; i < len(r.Onsets); i++ {
+			interval = math.Min(interval, r.Onsets[i]-r.Onsets[i-1])
+		}
+	}
+
+	if math.IsInf(interval, 1) {
+		return 0
+	}
+	return interval
+}
+
+// PSTH is the peri-stimulus time histogram of every run: the firing rate
+// (Hz) in bins of bin ms from each onset to window ms after it. trials is
+// the number of onsets averaged over.
+func (h *RunHistory) PSTH(window, bin float64) (rates []float64, trials int) {
+	bins := int(math.Ceil(window / bin))
+	if bins < 1 {
+		return nil, 0
+	}
+
+	counts := make([]float64, bins)
+
+	for _, r := range h.Runs() {
+		for _, onset := range r.Onsets {
+			// Onsets too close to the end don't have a full window.
+			if onset+window > float64(r.Duration) {
+				continue
+			}
+			trials++
+
+			for _, t := range r.Spikes {
+				dt := t - onset
+				if dt >= 0 && dt < window {
+					counts[int(dt/bin)]++
+				}
+			}
+		}
+	}
+
+	rates = make([]float64, bins)
+	if trials == 0 {
+		return rates, 0
+	}
+
+	for i, c := range counts {
+		rates[i] = c / float64(trials) / (bin / 1000.0)
+	}
+
+	return rates, trials
+}
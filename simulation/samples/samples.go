@@ -41,11 +41,20 @@ type Sample struct {
 	Id int
 }
 
+// Keys of the weight samples.
+const (
+	ExcititoryKey = 0
+	InhibitoryKey = 1
+)
+
 type baseLane struct {
 	Id     int
 	Values []*Sample
 	Min    float64
 	Max    float64
+
+	// False until a value is put after a reset.
+	bounded bool
 }
 
 // Lanes are trains of data for a given synapse or neuron.
@@ -110,6 +119,57 @@ func (s *Samples) Put(time float64, value interface{}, sid, key int) {
 	sp.Value = value
 	sp.Id = sid
 	sp.Key = key
+
+	// Widen the lane's bounds so live graphs can scale it before Post.
+	l.widen(value)
+}
+
+func (l *SamplesLane) widen(value interface{}) {
+	v, ok := value.(float64)
+	if !ok {
+		return
+	}
+
+	if !l.bounded {
+		l.Min, l.Max = v, v
+		l.bounded = true
+		return
+	}
+
+	l.Min = math.Min(l.Min, v)
+	l.Max = math.Max(l.Max, v)
+}
+
+// ResetBounds forgets the bounds of every lane. They're taken again from
+// the values put after it.
+func (s *Samples) ResetBounds() {
+	it := s.lanes.Iterator()
+	for it.Next() {
+		lane := it.Value().(*SamplesLane)
+		lane.Min, lane.Max = 0, 0
+		lane.bounded = false
+	}
+}
+
+// CopyTo copies the steps from up to, but not including, to into the
+// same lanes of view, widening view's bounds. Copying from 0 starts
+// view's bounds over.
+func (s *Samples) CopyTo(view *Samples, from, to int) {
+	if from == 0 {
+		view.ResetBounds()
+	}
+
+	it := s.lanes.Iterator()
+	vit := view.lanes.Iterator()
+	for it.Next() && vit.Next() {
+		lane := it.Value().(*SamplesLane)
+		vlane := vit.Value().(*SamplesLane)
+
+		for t := from; t < to && t < len(lane.Values); t++ {
+			*vlane.Values[t] = *lane.Values[t]
+			vlane.widen(lane.Values[t].Value)
+		}
+	}
 }
 
 func (s *Samples) Post() {
@@ -134,11 +194,104 @@ func (s *Samples) Post() {
 
 		lane.Min = min
 		lane.Max = max
+		lane.bounded = true
 
 		// fmt.Printf("(%d) min: %f, max: %f\n", lane.Id, min, max)
 	}
 }
 
+// Histogram counts every lane's value at time t in bins between min and
+// max, a histogram per sample Key. Values outside are put in the end bins.
+func (s *Samples) Histogram(t int, min, max float64, bins int) map[int][]int {
+	hist := map[int][]int{}
+
+	it := s.lanes.Iterator()
+	for it.Next() {
+		lane := it.Value().(*SamplesLane)
+		if t < 0 || t >= len(lane.Values) || lane.Values[t].Value == nil {
+			continue
+		}
+
+		sp := lane.Values[t]
+		counts, ok := hist[sp.Key]
+		if !ok {
+			counts = make([]int, bins)
+			hist[sp.Key] = counts
+		}
+
+		bin := 0
+		if max > min {
+			bin = int((sp.Value.(float64) - min) / (max - min) * float64(bins))
+		}
+		bin = int(math.Max(0, math.Min(float64(bins-1), float64(bin))))
+		counts[bin]++
+	}
+
+	return hist
+}
+
+// State captures the first size samples of every lane for a checkpoint.
+func (s *Samples) State(size int) interface{} {
+	lanes := []interface{}{}
+
+	it := s.lanes.Iterator()
+	for it.Next() {
+		lane := it.Value().(*SamplesLane)
+
+		values := make([]interface{}, size)
+		isInt := false
+		key := 0
+		for i := 0; i < size; i++ {
+			sp := lane.Values[i]
+			values[i] = sp.Value
+			if _, ok := sp.Value.(int); ok {
+				isInt = true
+			}
+			key = sp.Key
+		}
+
+		lanes = append(lanes, map[string]interface{}{
+			"Id":     lane.Id,
+			"Key":    key,
+			"Int":    isInt,
+			"Values": values,
+		})
+	}
+
+	return lanes
+}
+
+// RestoreState puts back samples captured by State. json turns ints
+// into float64s so lanes that held ints are converted back.
+func (s *Samples) RestoreState(state interface{}) {
+	lanes := state.([]interface{})
+
+	it := s.lanes.Iterator()
+	i := 0
+	for it.Next() {
+		lane := it.Value().(*SamplesLane)
+		jmap := lanes[i].(map[string]interface{})
+		i++
+
+		key := int(jmap["Key"].(float64))
+		isInt := jmap["Int"].(bool)
+
+		for t, v := range jmap["Values"].([]interface{}) {
+			sp := lane.Values[t]
+			sp.Time = float64(t)
+			sp.Id = lane.Id
+			sp.Key = key
+			if v == nil {
+				sp.Value = nil
+			} else if isInt {
+				sp.Value = int(v.(float64))
+			} else {
+				sp.Value = v.(float64)
+			}
+		}
+	}
+}
+
 // =======================================================================
 // Window range functions
 // =======================================================================
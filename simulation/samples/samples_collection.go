@@ -1,11 +1,17 @@
 package samples
 
-import sll "github.com/emirpasic/gods/lists/singlylinkedlist"
+import (
+	"sync"
+
+	sll "github.com/emirpasic/gods/lists/singlylinkedlist"
+)
 
 // ---------------------------------------------------------
 // Allocated in run_reset.go Create()
 // ---------------------------------------------------------
 
+// Sim is what the graphs draw. While a run goes it's a copy, made on the
+// GUI's thread, of the samples the sim writes, see CopyTo.
 var Sim *SamplesCollection
 
 type SamplesCollection struct {
@@ -25,8 +31,17 @@ type SamplesCollection struct {
 
 	CellSamples *Samples
 
+	// 1.0 while a stimulus pattern is being presented, otherwise 0.0
+	PatternSamples *Samples // only one lane
+
+	// Index of the protocol phase, 0 when there isn't a protocol.
+	PhaseSamples *Samples // only one lane
+
 	// Collect all the samples that need post processing
 	postSamples *sll.List
+
+	// Held while a step writes the samples, and while they're copied.
+	guard sync.Mutex
 }
 
 func NewSamplesCollection(synCnt, size int) *SamplesCollection {
@@ -34,6 +49,8 @@ func NewSamplesCollection(synCnt, size int) *SamplesCollection {
 	sc.PoiSamples = NewSamples(synCnt, size)
 	sc.StimSamples = NewSamples(synCnt, size)
 	sc.CellSamples = NewSamples(1, size)
+	sc.PatternSamples = NewSamples(1, size)
+	sc.PhaseSamples = NewSamples(1, size)
 
 	sc.postSamples = sll.New()
 
@@ -69,3 +86,69 @@ func (sc *SamplesCollection) Post() {
 		s.Post()
 	}
 }
+
+// Lock holds off CopyTo, for example, while a step is written.
+func (sc *SamplesCollection) Lock() {
+	sc.guard.Lock()
+}
+
+func (sc *SamplesCollection) Unlock() {
+	sc.guard.Unlock()
+}
+
+// ResetBounds forgets the bounds of every lane, see Samples.ResetBounds.
+func (sc *SamplesCollection) ResetBounds() {
+	for _, s := range sc.All() {
+		s.ResetBounds()
+	}
+}
+
+// CopyTo copies the steps from up to to of every lane into view, which
+// must be the same size. It waits for the step being written.
+func (sc *SamplesCollection) CopyTo(view *SamplesCollection, from, to int) {
+	sc.Lock()
+	defer sc.Unlock()
+
+	views := view.All()
+	for name, s := range sc.All() {
+		s.CopyTo(views[name], from, to)
+	}
+}
+
+// All returns every Samples by name.
+func (sc *SamplesCollection) All() map[string]*Samples {
+	return map[string]*Samples{
+		"Poi":          sc.PoiSamples,
+		"Stim":         sc.StimSamples,
+		"Surge":        sc.SurgeSamples,
+		"Psp":          sc.PspSamples,
+		"NeuronPsp":    sc.NeuronPspSamples,
+		"NeuronAP":     sc.NeuronAPSamples,
+		"NeuronAPSlow": sc.NeuronAPSlowSamples,
+		"Weight":       sc.WeightSamples,
+		"Dt":           sc.DtSamples,
+		"NeuronDt":     sc.NeuronDtSamples,
+		"Cell":         sc.CellSamples,
+		"Pattern":      sc.PatternSamples,
+		"Phase":        sc.PhaseSamples,
+	}
+}
+
+// State captures the samples collected before time step size.
+func (sc *SamplesCollection) State(size int) interface{} {
+	m := map[string]interface{}{}
+	for name, s := range sc.All() {
+		m[name] = s.State(size)
+	}
+	return m
+}
+
+func (sc *SamplesCollection) RestoreState(state interface{}) {
+	jmap := state.(map[string]interface{})
+	for name, s := range sc.All() {
+		// Older checkpoints may not have every kind of sample.
+		if st, ok := jmap[name]; ok {
+			s.RestoreState(st)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package sweep
+
+import (
+	"math"
+
+	"github.com/wdevore/Deuron5/deuron"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+// Metrics summarizes a single run.
+type Metrics struct {
+	// Output firing rate in Hz over the whole run.
+	OutputRate float64
+
+	// Distribution of the synaptic weights at the end of the run.
+	WeightMean float64
+	WeightStd  float64
+	WeightMin  float64
+	WeightMax  float64
+
+	// Output rate while a pattern is presented versus while it isn't.
+	RateIn  float64
+	RateOut float64
+
+	// (RateIn - RateOut) / (RateIn + RateOut). 1.0 means the neuron only
+	// fires for the pattern, 0.0 means it doesn't care.
+	Selectivity float64
+}
+
+func collectMetrics(model *deuron.Model, sams *samples.SamplesCollection) *Metrics {
+	return CollectMetrics(model, sams, 0, len(firstLane(sams.CellSamples).Values))
+}
+
+// CollectMetrics summarizes the steps [start, end) of a run, for example,
+// a single phase of a protocol. The weights are those at the end.
+func CollectMetrics(model *deuron.Model, sams *samples.SamplesCollection, start, end int) *Metrics {
+	m := new(Metrics)
+
+	// Each step is TimeStep microseconds.
+	stepSecs := model.GetFloat("TimeStep") / 1000000.0
+
+	cell := firstLane(sams.CellSamples)
+	pattern := firstLane(sams.PatternSamples)
+
+	spikes := 0.0
+	spikesIn, stepsIn := 0.0, 0.0
+	spikesOut, stepsOut := 0.0, 0.0
+
+	for i := start; i < end; i++ {
+		spike := toFloat(cell.Values[i].Value)
+		spikes += spike
+
+		if toFloat(pattern.Values[i].Value) == 1.0 {
+			spikesIn += spike
+			stepsIn++
+		} else {
+			spikesOut += spike
+			stepsOut++
+		}
+	}
+
+	m.OutputRate = rate(spikes, float64(end-start)*stepSecs)
+	m.RateIn = rate(spikesIn, stepsIn*stepSecs)
+	m.RateOut = rate(spikesOut, stepsOut*stepSecs)
+
+	if m.RateIn+m.RateOut > 0.0 {
+		m.Selectivity = (m.RateIn - m.RateOut) / (m.RateIn + m.RateOut)
+	}
+
+	// Final weight of each synapse.
+	weights := []float64{}
+	it := sams.WeightSamples.GetLanes().Iterator()
+	for it.Next() {
+		lane := it.Value().(*samples.SamplesLane)
+		for i := end - 1; i >= 0; i-- {
+			if lane.Values[i].Value != nil {
+				weights = append(weights, toFloat(lane.Values[i].Value))
+				break
+			}
+		}
+	}
+
+	if len(weights) > 0 {
+		m.WeightMin = math.Inf(1)
+		m.WeightMax = math.Inf(-1)
+		sum := 0.0
+		for _, w := range weights {
+			sum += w
+			m.WeightMin = math.Min(m.WeightMin, w)
+			m.WeightMax = math.Max(m.WeightMax, w)
+		}
+		m.WeightMean = sum / float64(len(weights))
+
+		variance := 0.0
+		for _, w := range weights {
+			variance += (w - m.WeightMean) * (w - m.WeightMean)
+		}
+		m.WeightStd = math.Sqrt(variance / float64(len(weights)))
+	}
+
+	return m
+}
+
+func firstLane(sams *samples.Samples) *samples.SamplesLane {
+	lane, _ := sams.GetLanes().Get(0)
+	return lane.(*samples.SamplesLane)
+}
+
+func toFloat(v interface{}) float64 {
+	switch f := v.(type) {
+	case float64:
+		return f
+	case int:
+		return float64(f)
+	}
+	return 0.0
+}
+
+func rate(spikes, secs float64) float64 {
+	if secs <= 0.0 {
+		return 0.0
+	}
+	return spikes / secs
+}
@@ -0,0 +1,154 @@
+package sweep
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"sort"
+)
+
+// Spec describes which model parameters to vary. Either Grid or Random
+// (or both) can be given. For example:
+//
+//	{
+//		"Workers": 4,
+//		"Grid": {
+//			"threshold": [15.0, 20.0, 25.0],
+//			"taoP": [10.0, 20.0]
+//		},
+//		"Random": {
+//			"Samples": 10,
+//			"Seed": 1963,
+//			"Ranges": {"ama": [0.5, 2.0]}
+//		}
+//	}
+type Spec struct {
+	// How many simulations run in parallel. Defaults to 1.
+	Workers int
+
+	// Every combination of values is run.
+	Grid map[string][]float64
+
+	Random *RandomSpec
+}
+
+// RandomSpec draws Samples uniform points from each [min, max] range.
+type RandomSpec struct {
+	Samples int
+	Seed    int64
+	Ranges  map[string][2]float64
+}
+
+// Job is a single point in parameter space.
+type Job struct {
+	ID        int
+	Overrides map[string]float64
+}
+
+// LoadSpec reads a sweep spec from a json file.
+func LoadSpec(fileName string) (*Spec, error) {
+	specFile, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer specFile.Close()
+
+	byteValue, err := ioutil.ReadAll(specFile)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := new(Spec)
+	err = json.Unmarshal(byteValue, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+// Keys returns the parameter names being varied in a stable order.
+func (sp *Spec) Keys() []string {
+	set := map[string]bool{}
+	for k := range sp.Grid {
+		set[k] = true
+	}
+	if sp.Random != nil {
+		for k := range sp.Random.Ranges {
+			set[k] = true
+		}
+	}
+
+	keys := []string{}
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Jobs expands the spec into the individual runs.
+func (sp *Spec) Jobs() ([]*Job, error) {
+	jobs := []*Job{}
+
+	if len(sp.Grid) > 0 {
+		keys := []string{}
+		for k := range sp.Grid {
+			if len(sp.Grid[k]) == 0 {
+				return nil, fmt.Errorf("Grid key (%s) has no values", k)
+			}
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		// Walk the grid like an odometer.
+		index := make([]int, len(keys))
+		for {
+			job := &Job{ID: len(jobs), Overrides: map[string]float64{}}
+			for i, k := range keys {
+				job.Overrides[k] = sp.Grid[k][index[i]]
+			}
+			jobs = append(jobs, job)
+
+			i := len(keys) - 1
+			for ; i >= 0; i-- {
+				index[i]++
+				if index[i] < len(sp.Grid[keys[i]]) {
+					break
+				}
+				index[i] = 0
+			}
+			if i < 0 {
+				break
+			}
+		}
+	}
+
+	if sp.Random != nil {
+		keys := []string{}
+		for k, r := range sp.Random.Ranges {
+			if r[0] > r[1] {
+				return nil, fmt.Errorf("Random key (%s) has min > max", k)
+			}
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		ran := rand.New(rand.NewSource(sp.Random.Seed))
+
+		for s := 0; s < sp.Random.Samples; s++ {
+			job := &Job{ID: len(jobs), Overrides: map[string]float64{}}
+			for _, k := range keys {
+				r := sp.Random.Ranges[k]
+				job.Overrides[k] = r[0] + ran.Float64()*(r[1]-r[0])
+			}
+			jobs = append(jobs, job)
+		}
+	}
+
+	return jobs, nil
+}
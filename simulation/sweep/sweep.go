@@ -0,0 +1,135 @@
+package sweep
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/wdevore/Deuron5/deuron"
+	"github.com/wdevore/Deuron5/simulation/runreset"
+)
+
+/*
+A sweep runs many headless RunResetSims, each with its own copy of
+the model and its own samples, and collects a summary of each run.
+Instead of nudging threshold, taoP, ama etc. one at a time from the
+panels a spec describes a grid or random samples of them.
+*/
+
+// Result pairs a job with what came out of it.
+type Result struct {
+	Job *Job
+
+	// The master seed the run used.
+	Seed int64
+
+	Metrics *Metrics
+}
+
+// Sweep runs the jobs of a spec against a base model.
+type Sweep struct {
+	spec *Spec
+	base *deuron.Model
+}
+
+// NewSweep creates a sweep. The base model isn't modified, each job
+// works on a clone of it.
+func NewSweep(spec *Spec, base *deuron.Model) *Sweep {
+	sw := new(Sweep)
+	sw.spec = spec
+	sw.base = base
+	return sw
+}
+
+// Run runs all the jobs and returns the results in job order.
+func (sw *Sweep) Run() ([]*Result, error) {
+	jobs, err := sw.spec.Jobs()
+	if err != nil {
+		return nil, err
+	}
+
+	workers := sw.spec.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]*Result, len(jobs))
+
+	jobChan := make(chan *Job)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobChan {
+				results[job.ID] = sw.runJob(job)
+			}
+		}()
+	}
+
+	for _, job := range jobs {
+		jobChan <- job
+	}
+	close(jobChan)
+
+	wg.Wait()
+
+	return results, nil
+}
+
+func (sw *Sweep) runJob(job *Job) *Result {
+	fmt.Printf("Sweep job (%d) %v\n", job.ID, job.Overrides)
+
+	model := sw.base.Clone()
+
+	// The seeds are derived when the sim is created.
+	if seed, ok := job.Overrides["Seed"]; ok {
+		model.SetFloat("Seed", seed)
+	}
+
+	sim := runreset.NewHeadlessRunResetSim(model)
+
+	// Create loads the stimulus settings into the model so the overrides
+	// are applied afterwards.
+	sim.Create()
+
+	for key, value := range job.Overrides {
+		if key == "Seed" {
+			continue
+		}
+
+		sim.Override(key, value)
+	}
+
+	sim.RunPause()
+
+	return &Result{Job: job, Seed: sim.Context().Seed, Metrics: collectMetrics(model, sim.Samples())}
+}
+
+// WriteCSV writes the results as a table, one row per job.
+func WriteCSV(w io.Writer, keys []string, results []*Result) {
+	header := append([]string{"Job", "Seed"}, keys...)
+	header = append(header, "OutputRate", "RateIn", "RateOut", "Selectivity",
+		"WeightMean", "WeightStd", "WeightMin", "WeightMax")
+	fmt.Fprintln(w, strings.Join(header, ","))
+
+	for _, r := range results {
+		row := []string{fmt.Sprintf("%d", r.Job.ID), fmt.Sprintf("%d", r.Seed)}
+		for _, k := range keys {
+			// Grid and Random jobs may vary different keys.
+			if v, ok := r.Job.Overrides[k]; ok {
+				row = append(row, fmt.Sprintf("%f", v))
+			} else {
+				row = append(row, "")
+			}
+		}
+		m := r.Metrics
+		for _, v := range []float64{m.OutputRate, m.RateIn, m.RateOut, m.Selectivity,
+			m.WeightMean, m.WeightStd, m.WeightMin, m.WeightMax} {
+			row = append(row, fmt.Sprintf("%f", v))
+		}
+		fmt.Fprintln(w, strings.Join(row, ","))
+	}
+}
@@ -0,0 +1,36 @@
+package tests
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/wdevore/Deuron5/simulation/runreset"
+)
+
+func Test_ResumeIsBitIdentical(t *testing.T) {
+	chdirRoot(t)
+
+	base := loadModel(t)
+
+	uninterrupted := runHeadless(base.Clone())
+
+	file := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	first := runreset.NewHeadlessRunResetSim(base.Clone())
+	first.Create()
+	first.Reset()
+	first.RunUntil(base.GetFloat("Samples") / 2)
+	if err := first.Checkpoint(file); err != nil {
+		t.Fatal(err)
+	}
+
+	resumed := runreset.NewHeadlessRunResetSim(base.Clone())
+	if err := resumed.Resume(file); err != nil {
+		t.Fatal(err)
+	}
+	resumed.Continue()
+
+	if diffs := countDifferences(uninterrupted, resumed.Samples()); diffs != 0 {
+		t.Errorf("expected resumed run to match, found %d differences", diffs)
+	}
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wdevore/Deuron5/cell/stimulus"
+)
+
+// binCounts steps every lane together and counts their spikes in bins.
+func binCounts(lanes []stimulus.IPatternStream, steps, bin int) [][]float64 {
+	counts := make([][]float64, len(lanes))
+	for i := range counts {
+		counts[i] = make([]float64, steps/bin)
+	}
+
+	for s := 0; s < steps; s++ {
+		for i, l := range lanes {
+			l.Step()
+			counts[i][s/bin] += float64(l.Output())
+		}
+	}
+
+	return counts
+}
+
+func pearson(a, b []float64) float64 {
+	n := float64(len(a))
+	ma, mb := 0.0, 0.0
+	for i := range a {
+		ma += a[i]
+		mb += b[i]
+	}
+	ma /= n
+	mb /= n
+
+	cov, va, vb := 0.0, 0.0, 0.0
+	for i := range a {
+		cov += (a[i] - ma) * (b[i] - mb)
+		va += (a[i] - ma) * (a[i] - ma)
+		vb += (b[i] - mb) * (b[i] - mb)
+	}
+
+	return cov / math.Sqrt(va*vb)
+}
+
+func Test_CorrelatedGroup(t *testing.T) {
+	for _, c := range []float64{0.1, 0.5} {
+		group, err := stimulus.NewCorrelatedGroup(23, 0.01, c, 2, 1.0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lanes := []stimulus.IPatternStream{group.NewLane(), group.NewLane(), group.NewLane()}
+
+		steps := 400000
+		counts := binCounts(lanes, steps, 200)
+
+		for i := range lanes {
+			total := 0.0
+			for _, v := range counts[i] {
+				total += v
+			}
+			within(t, "lane", int(total), 0.01*float64(steps))
+		}
+
+		for i := 1; i < len(lanes); i++ {
+			if r := pearson(counts[0], counts[i]); math.Abs(r-c) > 0.08 {
+				t.Errorf("c %0.1f: expected a correlation of about %0.1f between lanes 0 and %d, got %f", c, c, i, r)
+			}
+		}
+	}
+}
+
+func Test_CorrelatedSpecIgnoresMissingSynapses(t *testing.T) {
+	spec := &stimulus.CorrelationSpec{Synapses: []int{0, 1, 5, -1}, Rate: 0.01, Correlation: 0.5}
+
+	lanes, err := spec.Build(3, 1.0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lanes) != 2 || lanes[0] == nil || lanes[1] == nil {
+		t.Errorf("expected lanes for synapses 0 and 1 only, got %v", lanes)
+	}
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wdevore/Deuron5/cell/stimulus"
+	"github.com/wdevore/Deuron5/deuron"
+)
+
+func generate(t *testing.T, spec *stimulus.GeneratorSpec) *stimulus.StimulusFile {
+	sf, err := stimulus.Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sf
+}
+
+func Test_GeneratorShape(t *testing.T) {
+	spec := &stimulus.GeneratorSpec{Name: "gen", Lanes: 8, Length: 30, SpikesPerLane: 6, Patterns: 3, Overlap: 0.5, Seed: 7}
+	sf := generate(t, spec)
+
+	if len(sf.Patterns) != 3 {
+		t.Fatalf("expected 3 patterns, got %d", len(sf.Patterns))
+	}
+
+	for _, p := range sf.Patterns {
+		if len(p.Lanes) != 8 {
+			t.Fatalf("expected 8 lanes, got %d", len(p.Lanes))
+		}
+		for i, lane := range p.Lanes {
+			if lane.Synapse != i || len(lane.Spikes) != 30 || strings.Count(lane.Spikes, "|") != 6 {
+				t.Errorf("pattern %s lane %d: bad lane %+v", p.Name, i, lane)
+			}
+		}
+	}
+
+	// Half the lanes of the other patterns repeat the first.
+	for _, p := range sf.Patterns[1:] {
+		shared := 0
+		for i, lane := range p.Lanes {
+			if lane.Spikes == sf.Patterns[0].Lanes[i].Spikes {
+				shared++
+			}
+		}
+		if shared < 4 {
+			t.Errorf("pattern %s: expected at least 4 shared lanes, got %d", p.Name, shared)
+		}
+	}
+}
+
+func Test_GeneratorIsSeeded(t *testing.T) {
+	spec := &stimulus.GeneratorSpec{Name: "gen", Lanes: 10, Length: 25, SpikesPerLane: 5, Patterns: 2, Seed: 11}
+
+	a := generate(t, spec)
+	b := generate(t, spec)
+
+	spec.Seed++
+	c := generate(t, spec)
+
+	same := func(x, y *stimulus.StimulusFile) bool {
+		for p := range x.Patterns {
+			for l := range x.Patterns[p].Lanes {
+				if x.Patterns[p].Lanes[l].Spikes != y.Patterns[p].Lanes[l].Spikes {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	if !same(a, b) {
+		t.Error("expected the same seed to give the same patterns")
+	}
+	if same(a, c) {
+		t.Error("expected a different seed to give different patterns")
+	}
+}
+
+// The generated patterns take turns, they're never presented together.
+func Test_GeneratedPatternsTakeTurns(t *testing.T) {
+	chdirRoot(t)
+
+	spec := &stimulus.GeneratorSpec{Name: "gen", Lanes: 4, Length: 10, SpikesPerLane: 3, Patterns: 3, Seed: 5}
+	sf := generate(t, spec)
+
+	model := loadModel(t)
+	ctx := deuron.NewContext(model, nil)
+
+	presenters, err := sf.Build(ctx, ".", 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range presenters {
+		p.Reset()
+	}
+
+	counts := make([]int, len(presenters))
+
+	// Two rounds of 3 patterns, each 100ms long and 200ms apart.
+	for step := 0; step < 1200; step++ {
+		presenting := 0
+		for i, p := range presenters {
+			was := p.IsPresenting()
+			p.Step()
+			if p.IsPresenting() {
+				presenting++
+				if !was {
+					counts[i]++
+				}
+			}
+		}
+		if presenting > 1 {
+			t.Fatalf("expected one pattern at a time, %d at step %d", presenting, step)
+		}
+	}
+
+	for i, c := range counts {
+		if c != 2 {
+			t.Errorf("pattern %d: expected 2 presentations, got %d", i+1, c)
+		}
+	}
+}
+
+func Test_GeneratorWritesIntoDir(t *testing.T) {
+	dir := t.TempDir()
+
+	spec := &stimulus.GeneratorSpec{Name: "gen", Lanes: 2, Length: 10, SpikesPerLane: 2, Patterns: 1, Seed: 1}
+	if _, err := stimulus.WriteGenerated(dir, spec); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := stimulus.LoadStimulusFile(filepath.Join(dir, "gen.patterns.json")); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,92 @@
+package graphs
+
+// The graphs package links SDL so these tests are kept out of the tests
+// package, which doesn't need it.
+
+import (
+	"encoding/json"
+	"image"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/wdevore/Deuron5/deuron"
+	"github.com/wdevore/Deuron5/deuron/app/graphs"
+	"github.com/wdevore/Deuron5/simulation/runreset"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+// runHeadless runs a short sim and hands its samples to the graphs, like
+// cmd/graphs does.
+func runHeadless(t *testing.T) {
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+
+	byteValue, err := ioutil.ReadFile("neuron.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jsonMap := make(map[string]interface{})
+	if err := json.Unmarshal(byteValue, &jsonMap); err != nil {
+		t.Fatal(err)
+	}
+
+	deuron.SimModel.LoadSettings(jsonMap)
+	deuron.SimModel.SetFloat("Samples", 500)
+
+	model := deuron.SimModel.Clone()
+	sim := runreset.NewHeadlessRunResetSim(model)
+	sim.Create()
+	sim.RunPause()
+
+	deuron.SimModel.Load(model.ToJSON())
+	samples.Sim = sim.Samples()
+	samples.Runs = sim.Runs()
+}
+
+// drawn is true if img has more than one colour.
+func drawn(img *image.RGBA) bool {
+	first := img.RGBAAt(0, 0)
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if img.RGBAAt(x, y) != first {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func Test_RenderAndComposite(t *testing.T) {
+	runHeadless(t)
+
+	images := []*image.RGBA{}
+	for _, name := range []string{"weight", "neuronpsp"} {
+		img, err := graphs.Render(name, 400, 100, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if img.Bounds().Dx() != 400 || img.Bounds().Dy() != 100 {
+			t.Errorf("%s: expected 400x100, got %v", name, img.Bounds())
+		}
+		if !drawn(img) {
+			t.Errorf("%s: expected something drawn", name)
+		}
+		images = append(images, img)
+	}
+
+	if _, err := graphs.Render("nothing", 400, 100, nil); err == nil {
+		t.Error("expected an unknown graph to fail")
+	}
+
+	c := graphs.Composite(images)
+	if c.Bounds().Dx() != 400 || c.Bounds().Dy() != 200 {
+		t.Errorf("expected the composite to be 400x200, got %v", c.Bounds())
+	}
+	if c.RGBAAt(10, 150) != images[1].RGBAAt(10, 50) {
+		t.Error("expected the second image below the first")
+	}
+}
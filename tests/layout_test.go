@@ -0,0 +1,25 @@
+package tests
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+// The app builds in a copy of layout.json for when it's missing.
+func Test_DefaultLayoutMatchesLayoutFile(t *testing.T) {
+	chdirRoot(t)
+
+	layout, err := ioutil.ReadFile("layout.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	builtIn, err := ioutil.ReadFile("deuron/app/default_layout.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(layout, builtIn) {
+		t.Error("deuron/app/default_layout.json should be a copy of layout.json")
+	}
+}
@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/wdevore/Deuron5/deuron"
+	"github.com/wdevore/Deuron5/simulation/runreset"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+func Test_HeadKeepsNewestStep(t *testing.T) {
+	chdirRoot(t)
+
+	model := loadModel(t)
+	deuron.SimModel.Load(model.ToJSON())
+	deuron.SimModel.SetFloat("Samples", 100)
+	defer func() { samples.Sim = nil }()
+
+	sim := runreset.NewRunResetSim().(*runreset.RunResetSim)
+	sim.Create()
+
+	// Nobody reads the head while the sim steps, it mustn't block.
+	for i := 0; i < 20; i++ {
+		sim.Step()
+	}
+
+	select {
+	case head := <-sim.Head():
+		if head != 19 {
+			t.Errorf("expected the head at 19, got %f", head)
+		}
+	default:
+		t.Fatal("expected a head")
+	}
+
+	select {
+	case head := <-sim.Head():
+		t.Errorf("expected only the newest head, got %f too", head)
+	default:
+	}
+
+	// Lanes are scaled as they're written, before PostProcess.
+	lane, _ := sim.Samples().WeightSamples.GetLanes().Get(0)
+	if lane.(*samples.SamplesLane).Max <= 0 {
+		t.Error("expected the weight lane's max to follow the samples")
+	}
+}
+
+func Test_BoundsStartOverAtReset(t *testing.T) {
+	sams := samples.NewSamples(1, 4)
+	sams.Put(0, 5.0, 0, 0)
+	sams.Put(1, 7.0, 0, 0)
+
+	sams.ResetBounds()
+	sams.Put(0, 2.0, 0, 0)
+	sams.Put(1, 3.0, 0, 0)
+
+	lane, _ := sams.GetLanes().Get(0)
+	l := lane.(*samples.SamplesLane)
+	if l.Min != 2 || l.Max != 3 {
+		t.Errorf("expected bounds (2, 3), got (%f, %f)", l.Min, l.Max)
+	}
+}
+
+// The graphs draw a copy made while the sim runs in the background. Run
+// with -race.
+func Test_CopyWhileRunning(t *testing.T) {
+	chdirRoot(t)
+
+	model := loadModel(t)
+	deuron.SimModel.Load(model.ToJSON())
+	deuron.SimModel.SetFloat("Samples", 5000)
+	deuron.SimModel.SetFloat("AutoRunPause", 0)
+	defer func() {
+		samples.Sim = nil
+		samples.Runs.Clear()
+	}()
+
+	sim := runreset.NewRunResetSim().(*runreset.RunResetSim)
+	sim.Create()
+	view := samples.Sim
+
+	if !sim.StartRun() {
+		t.Fatal("expected the run to start")
+	}
+
+	shown := 0
+	copies := 0
+	deadline := time.Now().Add(30 * time.Second)
+	for sim.RunState() != deuron.SimIdle {
+		if time.Now().After(deadline) {
+			t.Fatal("the run didn't end")
+		}
+
+		select {
+		case head := <-sim.Head():
+			end := int(head) + 1
+			sim.Samples().CopyTo(view, shown, end)
+			shown = end
+			copies++
+
+			// What's copied can be drawn while the sim carries on.
+			lane, _ := view.WeightSamples.GetLanes().Get(0)
+			l := lane.(*samples.SamplesLane)
+			for i := 0; i < end; i++ {
+				if l.Values[i].Value == nil {
+					t.Fatalf("expected a copied weight at %d of %d", i, end)
+				}
+			}
+		default:
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	select {
+	case head := <-sim.Head():
+		sim.Samples().CopyTo(view, shown, int(head)+1)
+	default:
+	}
+
+	if copies == 0 {
+		t.Error("expected copies while running")
+	}
+
+	// Once it's all copied it's the same as the post processed run.
+	for name, s := range sim.Samples().All() {
+		vit := view.All()[name].GetLanes().Iterator()
+		it := s.GetLanes().Iterator()
+		for it.Next() && vit.Next() {
+			l := it.Value().(*samples.SamplesLane)
+			vl := vit.Value().(*samples.SamplesLane)
+			min, max := math.Inf(1), math.Inf(-1)
+			for i := range l.Values {
+				if *l.Values[i] != *vl.Values[i] {
+					t.Fatalf("%s lane %d differs at %d", name, l.Id, i)
+				}
+				if v, ok := l.Values[i].Value.(float64); ok {
+					min, max = math.Min(min, v), math.Max(max, v)
+				}
+			}
+			if !math.IsInf(min, 1) && (vl.Min != min || vl.Max != max) {
+				t.Errorf("%s lane %d bounds (%f, %f), expected (%f, %f)",
+					name, l.Id, vl.Min, vl.Max, min, max)
+			}
+		}
+	}
+}
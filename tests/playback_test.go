@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wdevore/Deuron5/cell/stimulus"
+)
+
+// spikeSteps returns the steps (from 0) that had a spike.
+func spikeSteps(stream stimulus.IPatternStream, steps int) []int {
+	out := []int{}
+	for i := 0; i < steps; i++ {
+		stream.Step()
+		if stream.Output() == 1 {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func Test_PlaybackFromCSV(t *testing.T) {
+	dir := t.TempDir() + "/"
+	csv := "channel,time\n0,2\n1,0.5\n0,7.5\n1,3\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "rec.csv"), []byte(csv), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := &stimulus.PlaybackSpec{File: "rec.csv", Scale: 2, Offset: 1,
+		Lanes: []*stimulus.ChannelLane{{Channel: 0, Synapse: 3}}}
+
+	streams, err := spec.Build(dir, 1.0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := streams[3]
+	if s == nil || s.Id() != 3 {
+		t.Fatal("expected channel 0 to feed synapse 3")
+	}
+
+	// 1 + 2*2 = 5 and 1 + 2*7.5 = 16
+	if got := spikeSteps(s, 30); !reflect.DeepEqual(got, []int{5, 16}) {
+		t.Errorf("expected spikes at 5 and 16, got %v", got)
+	}
+	if !s.IsComplete() {
+		t.Error("expected the playback to complete")
+	}
+}
+
+func Test_PlaybackLoops(t *testing.T) {
+	dir := t.TempDir() + "/"
+	txt := "1 4\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "rec.txt"), []byte(txt), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := &stimulus.PlaybackSpec{File: "rec.txt", Loop: true, Period: 10}
+	streams, err := spec.Build(dir, 1.0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := streams[0]
+	if got := spikeSteps(s, 25); !reflect.DeepEqual(got, []int{1, 4, 11, 14, 21, 24}) {
+		t.Errorf("expected the recording to repeat every 10ms, got %v", got)
+	}
+
+	s.Reset()
+	if got := spikeSteps(s, 5); !reflect.DeepEqual(got, []int{1, 4}) {
+		t.Errorf("expected reset to restart the recording, got %v", got)
+	}
+}
+
+func Test_PlaybackIgnoresMissingSynapses(t *testing.T) {
+	dir := t.TempDir() + "/"
+	if err := ioutil.WriteFile(filepath.Join(dir, "rec.txt"), []byte("1 4\n2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := &stimulus.PlaybackSpec{File: "rec.txt",
+		Lanes: []*stimulus.ChannelLane{{Channel: 0, Synapse: 1}, {Channel: 1, Synapse: 5}}}
+	streams, err := spec.Build(dir, 1.0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(streams) != 1 || streams[1] == nil {
+		t.Errorf("expected a lane for synapse 1 only, got %v", streams)
+	}
+}
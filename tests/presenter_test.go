@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wdevore/Deuron5/cell/stimulus"
+	"github.com/wdevore/Deuron5/deuron"
+)
+
+// onsets returns the steps at which presentations began.
+func onsets(p stimulus.IPatternPresenter, steps int) []int {
+	out := []int{}
+	was := false
+	for i := 0; i < steps; i++ {
+		p.Step()
+		if p.IsPresenting() && !was {
+			out = append(out, i)
+		}
+		was = p.IsPresenting()
+	}
+	return out
+}
+
+func lane(spikes string) stimulus.IPatternStream {
+	spk := stimulus.NewSpikeStream().(*stimulus.SpikeStream)
+	spk.SetSpikesFromString(spikes)
+	return spk
+}
+
+func Test_PeriodicPresenter(t *testing.T) {
+	p := stimulus.NewNPatternStream(1)
+	p.SetHertz(50)
+	p.Add(lane("|..|"))
+	p.Reset()
+
+	if got := onsets(p, 100); !reflect.DeepEqual(got, []int{0, 20, 40, 60, 80}) {
+		t.Errorf("expected a presentation every 20ms, got %v", got)
+	}
+
+	// A pattern longer than the period is presented back to back.
+	long := stimulus.NewNPatternStream(1)
+	long.SetPeriod(3)
+	long.Add(lane("|...|"))
+	long.Reset()
+
+	spikes := []int{}
+	for i := 0; i < 12; i++ {
+		long.Step()
+		long.Begin()
+		spikes = append(spikes, long.Stream().Output())
+	}
+	if !reflect.DeepEqual(spikes, []int{1, 0, 0, 0, 1, 0, 1, 0, 0, 0, 1, 0}) {
+		t.Errorf("unexpected back to back presentations %v", spikes)
+	}
+}
+
+func Test_ScheduledPresenter(t *testing.T) {
+	p, err := stimulus.NewScheduledPatternStream(1, []int{30, 5}, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Add(lane("|.|"))
+	p.Reset()
+
+	if got := onsets(p, 120); !reflect.DeepEqual(got, []int{5, 30, 55, 80, 105}) {
+		t.Errorf("unexpected onsets %v", got)
+	}
+
+	if _, err := stimulus.NewScheduledPatternStream(1, []int{60}, 50); err == nil {
+		t.Error("expected onsets past the repeat to be rejected")
+	}
+}
+
+// Without a Timing a Period is the delay after each presentation, as it
+// always has been, not the time from one onset to the next.
+func Test_UntimedPeriodIsADelay(t *testing.T) {
+	chdirRoot(t)
+
+	model := loadModel(t)
+	model.SetFloat("Hertz", 20)
+	ctx := deuron.NewContext(model, nil)
+
+	one := 1.0
+	sf := &stimulus.StimulusFile{
+		Version: stimulus.StimulusVersion,
+		Patterns: []*stimulus.PatternSpec{
+			{Name: "A", Period: 10, Probability: &one, Lanes: []*stimulus.LaneSpec{
+				{Synapse: 0, Spikes: "|........|"},
+			}},
+		},
+	}
+
+	presenters, err := sf.Build(ctx, ".", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	presenters[0].Reset()
+
+	got := onsets(presenters[0], 100)
+	if len(got) < 2 {
+		t.Fatalf("expected presentations, got %v", got)
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i]-got[i-1] <= 10+10 {
+			t.Errorf("expected a 10ms delay after each 10ms pattern, got onsets %v", got)
+			break
+		}
+	}
+}
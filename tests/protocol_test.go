@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/wdevore/Deuron5/simulation/protocol"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+func weightAt(sams *samples.SamplesCollection, lane, t int) float64 {
+	l, _ := sams.WeightSamples.GetLanes().Get(lane)
+	return l.(*samples.SamplesLane).Values[t].Value.(float64)
+}
+
+func Test_ProtocolPhases(t *testing.T) {
+	chdirRoot(t)
+
+	on, off := true, false
+	p := &protocol.Protocol{
+		Name: "test",
+		Phases: []*protocol.Phase{
+			{Name: "train", Duration: 1000, Stimulus: "stim_1", Learning: &on,
+				Overrides: map[string]float64{"threshold": 20.0}},
+			{Name: "frozen", Duration: 1000, Learning: &off},
+			{Name: "switch", Duration: 500, Stimulus: "stim_2", Learning: &on},
+		},
+	}
+
+	model := loadModel(t)
+	sim, results := protocol.Run(p, model)
+	sams := sim.Samples()
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 phase results, got %d", len(results))
+	}
+
+	phaseLane, _ := sams.PhaseSamples.GetLanes().Get(0)
+	values := phaseLane.(*samples.SamplesLane).Values
+	for _, c := range []struct{ t, phase int }{{0, 0}, {999, 0}, {1000, 1}, {1999, 1}, {2000, 2}, {2499, 2}} {
+		if v := values[c.t].Value.(float64); int(v) != c.phase {
+			t.Errorf("expected phase %d at %d, got %v", c.phase, c.t, v)
+		}
+	}
+
+	// The threshold override lets the neuron fire and learn in the first
+	// phase but no synapse's weight moves while learning is frozen.
+	synCnt := int(model.GetFloat("Synapse_Count"))
+	learnt := false
+	for syn := 0; syn < synCnt; syn++ {
+		if weightAt(sams, syn, 999) != weightAt(sams, syn, 0) {
+			learnt = true
+		}
+	}
+	if !learnt {
+		t.Error("expected weights to change while learning")
+	}
+
+	for syn := 0; syn < synCnt; syn++ {
+		w := weightAt(sams, syn, 1000)
+		for step := 1001; step < 2000; step++ {
+			if weightAt(sams, syn, step) != w {
+				t.Fatalf("synapse %d weight changed at %d while frozen", syn, step)
+			}
+		}
+	}
+
+	if model.GetString("Stimulus") != "stim_2" {
+		t.Errorf("expected stim_2 after the last phase, got %s", model.GetString("Stimulus"))
+	}
+}
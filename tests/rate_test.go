@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wdevore/Deuron5/cell/stimulus"
+)
+
+func countSpikes(stream stimulus.IPatternStream, steps int) int {
+	cnt := 0
+	for i := 0; i < steps; i++ {
+		stream.Step()
+		cnt += stream.Output()
+	}
+	return cnt
+}
+
+// within checks a poisson count is within 4 standard deviations.
+func within(t *testing.T, name string, cnt int, expected float64) {
+	if math.Abs(float64(cnt)-expected) > 4.0*math.Sqrt(expected) {
+		t.Errorf("%s: expected about %0.0f spikes, got %d", name, expected, cnt)
+	}
+}
+
+func Test_StepRateThinning(t *testing.T) {
+	rate := &stimulus.StepRate{Times: []float64{0, 50000}, Rates: []float64{0.005, 0.02}}
+	stream := stimulus.NewInhomogeneousPoissonStream(3, rate, 1.0)
+
+	// At 0.02/ms the chance of two candidates in one ms is small enough
+	// that the count is close to rate * time.
+	within(t, "low", countSpikes(stream, 50000), 250)
+	within(t, "high", countSpikes(stream, 50000), 1000)
+}
+
+func Test_SineRateThinning(t *testing.T) {
+	rate := &stimulus.SineRate{Base: 0.01, Amplitude: 0.008, Period: 1000}
+	stream := stimulus.NewInhomogeneousPoissonStream(5, rate, 1.0)
+
+	// The rising half of each cycle has (Base + 2*Amplitude/pi) * 500
+	// expected spikes.
+	rising := 0
+	falling := 0
+	for c := 0; c < 100; c++ {
+		rising += countSpikes(stream, 500)
+		falling += countSpikes(stream, 500)
+	}
+
+	half := 100 * 500.0
+	within(t, "rising", rising, (0.01+2*0.008/math.Pi)*half)
+	within(t, "falling", falling, (0.01-2*0.008/math.Pi)*half)
+}
+
+func Test_ThinningFollowsTheStepSize(t *testing.T) {
+	rate := &stimulus.StepRate{Times: []float64{0, 5000}, Rates: []float64{0.005, 0.02}}
+	stream := stimulus.NewInhomogeneousPoissonStream(7, rate, 0.1)
+
+	// 50000 steps of 0.1ms are 5000ms at each rate.
+	within(t, "low", countSpikes(stream, 50000), 25)
+	within(t, "high", countSpikes(stream, 50000), 100)
+}
+
+func Test_RateCurveInterpolates(t *testing.T) {
+	curve := &stimulus.CurveRate{Times: []float64{0, 100}, Rates: []float64{0.0, 0.01}}
+
+	if r := curve.Rate(50); math.Abs(r-0.005) > 1e-12 {
+		t.Errorf("expected 0.005, got %f", r)
+	}
+	if r := curve.Rate(200); r != 0.01 {
+		t.Errorf("expected the end rate to hold, got %f", r)
+	}
+}
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wdevore/Deuron5/cell/stimulus"
+)
+
+// isiStats runs a stream for duration ms and returns the rate and the CV
+// of the ISIs measured from the steps the spikes landed in.
+func isiStats(stream stimulus.IPatternStream, dt, duration float64) (rate, cv float64) {
+	steps := int(duration / dt)
+
+	isis := []float64{}
+	last := -1.0
+	for i := 0; i < steps; i++ {
+		stream.Step()
+		if stream.Output() == 1 {
+			t := float64(i) * dt
+			if last >= 0 {
+				isis = append(isis, t-last)
+			}
+			last = t
+		}
+	}
+
+	mean := 0.0
+	for _, isi := range isis {
+		mean += isi
+	}
+	mean /= float64(len(isis))
+
+	variance := 0.0
+	for _, isi := range isis {
+		variance += (isi - mean) * (isi - mean)
+	}
+	variance /= float64(len(isis) - 1)
+
+	return 1.0 / mean, math.Sqrt(variance) / mean
+}
+
+func checkRenewal(t *testing.T, name string, interval stimulus.IInterval, dt float64) {
+	stream := stimulus.NewRenewalStream(17, interval, dt)
+
+	rate, cv := isiStats(stream, dt, 1000000)
+
+	expectedRate := 1.0 / interval.Mean()
+	if math.Abs(rate-expectedRate)/expectedRate > 0.02 {
+		t.Errorf("%s dt %0.2f: expected rate %f, got %f", name, dt, expectedRate, rate)
+	}
+	if math.Abs(cv-interval.CV()) > 0.03 {
+		t.Errorf("%s dt %0.2f: expected CV %f, got %f", name, dt, interval.CV(), cv)
+	}
+}
+
+func Test_DeadTimeStatistics(t *testing.T) {
+	interval := &stimulus.DeadTimeInterval{Rate: 0.02, DeadTime: 5}
+	checkRenewal(t, "deadtime", interval, 1.0)
+	checkRenewal(t, "deadtime", interval, 0.1)
+}
+
+func Test_GammaStatistics(t *testing.T) {
+	for _, k := range []int{1, 4} {
+		interval := &stimulus.GammaInterval{Rate: 0.01, Order: k}
+		checkRenewal(t, "gamma", interval, 1.0)
+		checkRenewal(t, "gamma", interval, 0.25)
+	}
+}
@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/wdevore/Deuron5/deuron"
+	"github.com/wdevore/Deuron5/simulation/runreset"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+// The sim loads ./stimulus relative to the working directory.
+func chdirRoot(t *testing.T) {
+	if _, err := os.Stat("../neuron.json"); err == nil {
+		if err := os.Chdir(".."); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func loadModel(t *testing.T) *deuron.Model {
+	byteValue, err := ioutil.ReadFile("neuron.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jsonMap := make(map[string]interface{})
+	if err := json.Unmarshal(byteValue, &jsonMap); err != nil {
+		t.Fatal(err)
+	}
+
+	model := deuron.NewModel()
+	model.LoadSettings(jsonMap)
+
+	return model
+}
+
+func runHeadless(model *deuron.Model) *samples.SamplesCollection {
+	sim := runreset.NewHeadlessRunResetSim(model)
+	sim.Create()
+	sim.RunPause()
+	return sim.Samples()
+}
+
+func bits(v interface{}) uint64 {
+	switch f := v.(type) {
+	case float64:
+		return math.Float64bits(f)
+	case int:
+		return uint64(f)
+	}
+	return 0
+}
+
+// countDifferences compares every sample of every lane bit for bit.
+func countDifferences(a, b *samples.SamplesCollection) int {
+	diffs := 0
+	sb := b.All()
+
+	for name, sa := range a.All() {
+		la := sa.GetLanes()
+		lb := sb[name].GetLanes()
+		for i := 0; i < la.Size(); i++ {
+			va, _ := la.Get(i)
+			vb, _ := lb.Get(i)
+			laneA := va.(*samples.SamplesLane)
+			laneB := vb.(*samples.SamplesLane)
+			for s := range laneA.Values {
+				if bits(laneA.Values[s].Value) != bits(laneB.Values[s].Value) {
+					diffs++
+				}
+			}
+		}
+	}
+
+	return diffs
+}
+
+func Test_SameSeedIsBitIdentical(t *testing.T) {
+	chdirRoot(t)
+
+	base := loadModel(t)
+
+	first := runHeadless(base.Clone())
+	second := runHeadless(base.Clone())
+
+	if diffs := countDifferences(first, second); diffs != 0 {
+		t.Errorf("expected identical samples, found %d differences", diffs)
+	}
+}
+
+func Test_DifferentSeedDiffers(t *testing.T) {
+	chdirRoot(t)
+
+	base := loadModel(t)
+
+	first := runHeadless(base.Clone())
+
+	model := base.Clone()
+	model.SetFloat("Seed", model.GetFloat("Seed")+1)
+	second := runHeadless(model)
+
+	if countDifferences(first, second) == 0 {
+		t.Error("expected a different seed to change the samples")
+	}
+}
@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wdevore/Deuron5/deuron"
+	"github.com/wdevore/Deuron5/simulation/runreset"
+)
+
+// waitFor polls until cond holds, failing after a few seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for i := 0; i < 500; i++ {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func Test_RunPausesBetweenSteps(t *testing.T) {
+	chdirRoot(t)
+
+	model := loadModel(t)
+	model.SetFloat("Samples", 20000)
+	model.SetFloat("AutoRunPause", 0)
+
+	sim := runreset.NewHeadlessRunResetSim(model)
+	sim.Create()
+
+	if !sim.StartRun() {
+		t.Fatal("expected the run to start")
+	}
+	if sim.StartRun() {
+		t.Error("expected only one run at a time")
+	}
+
+	sim.PauseRun()
+	if sim.RunState() != deuron.SimPaused {
+		t.Fatalf("expected paused, got %s", sim.RunState())
+	}
+
+	// Paused, the epoch doesn't get to the end.
+	time.Sleep(100 * time.Millisecond)
+	if sim.RunState() != deuron.SimPaused || sim.Epochs() != 0 {
+		t.Errorf("expected to stay paused, got %s after %d epochs", sim.RunState(), sim.Epochs())
+	}
+
+	sim.ResumeRun()
+	waitFor(t, "the epoch to end", func() bool { return sim.RunState() == deuron.SimIdle })
+
+	if sim.Epochs() != 1 {
+		t.Errorf("expected 1 epoch, got %d", sim.Epochs())
+	}
+	if len(sim.Runs().Runs()) != 1 {
+		t.Errorf("expected 1 run recorded, got %d", len(sim.Runs().Runs()))
+	}
+}
+
+func Test_AutoRunLoopsUntilStopped(t *testing.T) {
+	chdirRoot(t)
+
+	model := loadModel(t)
+	model.SetFloat("Samples", 100)
+	model.SetFloat("AutoRunPause", 1)
+
+	sim := runreset.NewHeadlessRunResetSim(model)
+	sim.Create()
+	sim.StartRun()
+
+	waitFor(t, "several epochs", func() bool { return sim.Epochs() >= 3 })
+
+	sim.StopRun()
+	waitFor(t, "the run to stop", func() bool { return sim.RunState() == deuron.SimIdle })
+
+	// Stopped part way, the partial epoch isn't counted.
+	epochs := sim.Epochs()
+	time.Sleep(20 * time.Millisecond)
+	if sim.Epochs() != epochs {
+		t.Errorf("expected no epochs after stopping, %d then %d", epochs, sim.Epochs())
+	}
+}
+
+func Test_StopPartWayThroughAnEpoch(t *testing.T) {
+	chdirRoot(t)
+
+	model := loadModel(t)
+	model.SetFloat("Samples", 200000)
+	model.SetFloat("AutoRunPause", 0)
+
+	sim := runreset.NewHeadlessRunResetSim(model)
+	sim.Create()
+	sim.StartRun()
+
+	time.Sleep(20 * time.Millisecond)
+	sim.StopRun()
+	waitFor(t, "the run to stop", func() bool { return sim.RunState() == deuron.SimIdle })
+
+	if sim.Epochs() != 0 || len(sim.Runs().Runs()) != 0 {
+		t.Errorf("expected the partial epoch not to count, got %d epochs and %d runs", sim.Epochs(), len(sim.Runs().Runs()))
+	}
+	if sim.Time() <= 0 || sim.Time() >= 200000 {
+		t.Errorf("expected to stop part way, stopped at %f", sim.Time())
+	}
+}
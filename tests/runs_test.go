@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wdevore/Deuron5/simulation/runreset"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+func Test_RunHistoryPSTH(t *testing.T) {
+	sams := samples.NewSamplesCollection(1, 100)
+	for step := 0; step < 100; step++ {
+		presenting := 0.0
+		if (step >= 10 && step < 20) || (step >= 50 && step < 60) {
+			presenting = 1.0
+		}
+		sams.PatternSamples.Put(float64(step), presenting, 0, 0)
+
+		spike := 0.0
+		if step == 12 || step == 52 || step == 53 || step == 68 {
+			spike = 1.0
+		}
+		sams.CellSamples.Put(float64(step), spike, 0, 0)
+	}
+
+	h := samples.NewRunHistory(2)
+	h.Add(sams)
+
+	run := h.Runs()[0]
+	if len(run.Onsets) != 2 || run.Onsets[0] != 10 || run.Onsets[1] != 50 {
+		t.Fatalf("expected onsets at 10 and 50, got %v", run.Onsets)
+	}
+	if len(run.Spikes) != 4 {
+		t.Fatalf("expected 4 spikes, got %v", run.Spikes)
+	}
+	if h.Interval() != 40 {
+		t.Errorf("expected an interval of 40, got %f", h.Interval())
+	}
+
+	rates, trials := h.PSTH(40, 5)
+	if trials != 2 || len(rates) != 8 {
+		t.Fatalf("expected 2 trials of 8 bins, got %d of %d", trials, len(rates))
+	}
+
+	// 3 spikes in the first 5ms of 2 onsets, 1 at 18ms.
+	if math.Abs(rates[0]-300) > 1e-9 || math.Abs(rates[3]-100) > 1e-9 {
+		t.Errorf("unexpected rates %v", rates)
+	}
+
+	// Only the newest runs are kept.
+	h.Add(sams)
+	h.Add(sams)
+	if len(h.Runs()) != 2 {
+		t.Errorf("expected 2 runs kept, got %d", len(h.Runs()))
+	}
+}
+
+func Test_RunHistoryRecordsRuns(t *testing.T) {
+	chdirRoot(t)
+
+	model := loadModel(t)
+	model.SetFloat("Samples", 500)
+
+	sim := runreset.NewHeadlessRunResetSim(model)
+	sim.Create()
+	for i := 0; i < 3; i++ {
+		sim.RunPause()
+	}
+
+	runs := sim.Runs().Runs()
+	if len(runs) != 3 {
+		t.Fatalf("expected 3 runs, got %d", len(runs))
+	}
+
+	for _, run := range runs {
+		if run.Duration != 500 {
+			t.Errorf("expected a 500ms run, got %d", run.Duration)
+		}
+		if len(run.Onsets) == 0 {
+			t.Error("expected pattern onsets")
+		}
+	}
+
+	if len(samples.Runs.Runs()) != 0 {
+		t.Error("a headless sim shouldn't record the app's runs")
+	}
+}
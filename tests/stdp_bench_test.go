@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/wdevore/Deuron5/simulation/bench"
+)
+
+func Test_STDPBenchWindow(t *testing.T) {
+	chdirRoot(t)
+
+	spec := &bench.Spec{DeltaMin: -40, DeltaMax: 40, DeltaStep: 10, Frequencies: []float64{5}, Pairings: 5}
+
+	b, err := bench.NewBench(spec, loadModel(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	window := map[float64]float64{}
+	for _, p := range b.Run() {
+		window[p.Delta] = p.DeltaW
+	}
+
+	if len(window) != 9 {
+		t.Fatalf("expected 9 deltas, got %d", len(window))
+	}
+
+	// Pre before post potentiates, post before pre depresses.
+	if window[10] <= 0 || window[-10] >= 0 {
+		t.Errorf("expected LTP at +10 and LTD at -10, got %f and %f", window[10], window[-10])
+	}
+
+	// The further apart the smaller the change.
+	if window[40] >= window[10] {
+		t.Errorf("expected LTP to fall off, +10: %f +40: %f", window[10], window[40])
+	}
+	if window[-40] <= window[-10] {
+		t.Errorf("expected LTD to fall off, -10: %f -40: %f", window[-10], window[-40])
+	}
+}
+
+func Test_STDPBenchOverrides(t *testing.T) {
+	chdirRoot(t)
+
+	model := loadModel(t)
+
+	spec := &bench.Spec{DeltaMin: 30, DeltaMax: 30, DeltaStep: 1, Frequencies: []float64{5}, Pairings: 5}
+	b, err := bench.NewBench(spec, model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	short := b.Pair(5, 30).DeltaW
+
+	spec.Overrides = map[string]float64{"taoP": 60.0}
+	long := b.Pair(5, 30).DeltaW
+
+	if long <= short {
+		t.Errorf("expected a longer taoP to potentiate more at +30, got %f <= %f", long, short)
+	}
+}
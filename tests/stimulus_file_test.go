@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wdevore/Deuron5/cell/stimulus"
+	"github.com/wdevore/Deuron5/deuron"
+)
+
+const patternsJSON = `{
+	"Version": 1,
+	"Name": "mapped",
+	"Patterns": [
+		{
+			"Name": "A",
+			"Lanes": [
+				{"Name": "a0", "Synapse": 3, "Spikes": "|..."},
+				{"Name": "a1", "Synapse": 1, "Spikes": ".|.."}
+			]
+		}
+	]
+}`
+
+func writeFile(t *testing.T, fileName, text string) {
+	if err := ioutil.WriteFile(fileName, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_LoadStimulusPatternsFile(t *testing.T) {
+	dir := t.TempDir() + "/"
+	writeFile(t, filepath.Join(dir, "mapped.patterns.json"), patternsJSON)
+	// The json is preferred over the old grid.
+	writeFile(t, filepath.Join(dir, "mapped.txt"), "|\n|\n")
+
+	sf, err := stimulus.LoadStimulus(dir, "mapped")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sf.Name != "mapped" || len(sf.Patterns) != 1 || len(sf.Patterns[0].Lanes) != 2 {
+		t.Fatalf("unexpected stimulus %+v", sf)
+	}
+
+	p := sf.Patterns[0]
+	if *p.Probability != 1 {
+		t.Errorf("expected the Probability to default to 1, got %f", *p.Probability)
+	}
+	if p.Lanes[0].Synapse != 3 || p.Lanes[0].Spikes != "|..." {
+		t.Errorf("unexpected lane %+v", p.Lanes[0])
+	}
+}
+
+func Test_LoadStimulusRejectsVersion(t *testing.T) {
+	dir := t.TempDir() + "/"
+	writeFile(t, filepath.Join(dir, "new.patterns.json"), strings.Replace(patternsJSON, `"Version": 1`, `"Version": 2`, 1))
+
+	if _, err := stimulus.LoadStimulus(dir, "new"); err == nil {
+		t.Error("expected an unsupported version to be rejected")
+	}
+}
+
+// Without a patterns file the old grid is read, a line per synapse.
+func Test_LoadStimulusFallsBackToGrid(t *testing.T) {
+	chdirRoot(t)
+
+	byteValue, err := ioutil.ReadFile("stimulus/stim_2.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(string(byteValue), "\n"), "\n")
+
+	sf, err := stimulus.LoadStimulus("./stimulus/", "stim_2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sf.Patterns) != 1 || len(sf.Patterns[0].Lanes) != len(lines) {
+		t.Fatalf("expected a pattern of %d lanes, got %+v", len(lines), sf.Patterns)
+	}
+
+	for i, lane := range sf.Patterns[0].Lanes {
+		if lane.Synapse != i || lane.Spikes != lines[i] {
+			t.Errorf("lane %d: expected synapse %d and %s, got %+v", i, i, lines[i], lane)
+		}
+	}
+}
+
+// A lane's stream is the synapse it names, not its position.
+func Test_LaneSynapseMapping(t *testing.T) {
+	chdirRoot(t)
+
+	dir := t.TempDir() + "/"
+	writeFile(t, filepath.Join(dir, "mapped.patterns.json"), patternsJSON)
+
+	sf, err := stimulus.LoadStimulus(dir, "mapped")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	model := loadModel(t)
+	presenters, err := sf.Build(deuron.NewContext(model, nil), dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := presenters[0]
+	p.Reset()
+
+	// a0, on synapse 3, spikes a step before a1, on synapse 1.
+	first := map[int]int{}
+	for step := 0; step < 1000 && len(first) < 2; step++ {
+		p.Step()
+		for more := p.Begin(); more; more = p.Next() {
+			id := p.Stream().Id()
+			if _, ok := first[id]; !ok && p.Stream().Output() == 1 {
+				first[id] = step
+			}
+		}
+	}
+
+	if len(first) != 2 || first[1] != first[3]+1 {
+		t.Errorf("expected synapse 3 then 1 to spike, got first spikes %v", first)
+	}
+}
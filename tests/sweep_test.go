@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/wdevore/Deuron5/simulation/sweep"
+)
+
+func runSweep(t *testing.T, workers int) string {
+	model := loadModel(t)
+	model.SetFloat("Samples", 300)
+
+	spec := &sweep.Spec{
+		Workers: workers,
+		Grid: map[string][]float64{
+			"threshold": {15.0, 25.0},
+			"taoP":      {10.0, 20.0},
+		},
+	}
+
+	results, err := sweep.NewSweep(spec, model).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var csv bytes.Buffer
+	sweep.WriteCSV(&csv, spec.Keys(), results)
+	return csv.String()
+}
+
+func Test_SweepGrid(t *testing.T) {
+	chdirRoot(t)
+
+	csv := runSweep(t, 1)
+
+	rows := strings.Split(strings.TrimSpace(csv), "\n")
+	if len(rows) != 5 {
+		t.Fatalf("expected a header and 4 rows, got %d lines", len(rows))
+	}
+
+	// Job, Seed, taoP, threshold, ...
+	expected := [][2]string{
+		{"10.000000", "15.000000"},
+		{"10.000000", "25.000000"},
+		{"20.000000", "15.000000"},
+		{"20.000000", "25.000000"},
+	}
+	for i, row := range rows[1:] {
+		cols := strings.Split(row, ",")
+		if cols[2] != expected[i][0] || cols[3] != expected[i][1] {
+			t.Errorf("row %d: expected taoP %s and threshold %s, got %s", i, expected[i][0], expected[i][1], row)
+		}
+	}
+
+	// The jobs don't share anything so the workers don't change them.
+	if parallel := runSweep(t, 3); parallel != csv {
+		t.Errorf("expected the same results with 3 workers\n%s\ngot\n%s", csv, parallel)
+	}
+}
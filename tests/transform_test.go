@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wdevore/Deuron5/cell/stimulus"
+)
+
+func train(s string) []int {
+	out := make([]int, len(s))
+	for i, c := range s {
+		if c == '|' {
+			out[i] = 1
+		}
+	}
+	return out
+}
+
+func Test_StretchMatchesExpand(t *testing.T) {
+	expanded := stimulus.NewSpikeStream().(*stimulus.SpikeStream)
+	expanded.SetSpikesFromString("|..|.|")
+	expanded.Expand(2)
+
+	stretched := stimulus.NewSpikeStream().(*stimulus.SpikeStream)
+	stretched.SetSpikesFromString("|..|.|")
+	stretched.Stretch(3)
+
+	if expanded.ToString(false) != stretched.ToString(false) {
+		t.Errorf("expected %s, got %s", expanded.ToString(false), stretched.ToString(false))
+	}
+	if got := expanded.ToString(false); got != "|........|.....|.." {
+		t.Errorf("unexpected expansion %s", got)
+	}
+
+	// Clear is in pattern steps.
+	expanded.Clear(3)
+	if got := expanded.ToString(false); got != "|..............|.." {
+		t.Errorf("expected the scaled spike to be cleared, got %s", got)
+	}
+}
+
+func Test_FractionalStretch(t *testing.T) {
+	s := &stimulus.Stretch{Factor: 1.5}
+	if got := s.Apply(train("|.|.|..."), nil); !reflect.DeepEqual(got, train("|..|..|.....")) {
+		t.Errorf("unexpected stretch %v", got)
+	}
+}
+
+func Test_SeededPipelineIsPerPresentation(t *testing.T) {
+	base := train(strings.Repeat("..|..", 40))
+
+	p := stimulus.NewSeededPipeline(9, &stimulus.GaussianJitter{Sigma: 1}, &stimulus.Delete{Probability: 0.2}, &stimulus.Insert{Probability: 0.05})
+
+	first := p.Apply(base)
+	second := p.Apply(base)
+
+	if reflect.DeepEqual(first, second) {
+		t.Error("expected each presentation to get different noise")
+	}
+
+	p.Reseed(9)
+	if !reflect.DeepEqual(first, p.Apply(base)) || !reflect.DeepEqual(second, p.Apply(base)) {
+		t.Error("expected reseeding to repeat the presentations")
+	}
+}
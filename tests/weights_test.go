@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/wdevore/Deuron5/simulation/runreset"
+	"github.com/wdevore/Deuron5/simulation/samples"
+)
+
+func Test_WeightHistogram(t *testing.T) {
+	ws := samples.NewSamples(4, 2)
+	ws.Put(1, 0.0, 0, samples.ExcititoryKey)
+	ws.Put(1, 5.0, 1, samples.ExcititoryKey)
+	ws.Put(1, 10.0, 2, samples.ExcititoryKey)
+	ws.Put(1, 4.9, 3, samples.InhibitoryKey)
+
+	hist := ws.Histogram(1, 0, 10, 2)
+
+	exc, inh := hist[samples.ExcititoryKey], hist[samples.InhibitoryKey]
+	if exc[0] != 1 || exc[1] != 2 {
+		t.Errorf("expected excititory [1 2], got %v", exc)
+	}
+	if inh[0] != 1 || inh[1] != 0 {
+		t.Errorf("expected inhibitory [1 0], got %v", inh)
+	}
+
+	// Nothing has been sampled at 0.
+	if len(ws.Histogram(0, 0, 10, 2)) != 0 {
+		t.Error("expected an empty histogram")
+	}
+}
+
+func Test_WeightHistogramCountsEverySynapse(t *testing.T) {
+	chdirRoot(t)
+
+	model := loadModel(t)
+	model.SetFloat("Samples", 200)
+
+	sim := runreset.NewHeadlessRunResetSim(model)
+	sim.Create()
+	sim.RunPause()
+
+	hist := sim.Samples().WeightSamples.Histogram(199, model.GetFloat("weightMin"), model.GetFloat("weightMax"), 20)
+
+	total := 0
+	for _, counts := range hist {
+		for _, n := range counts {
+			total += n
+		}
+	}
+
+	if total != int(model.GetFloat("Synapse_Count")) {
+		t.Errorf("expected every synapse to be counted, got %d", total)
+	}
+}